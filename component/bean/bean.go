@@ -17,7 +17,10 @@
 package bean
 
 import (
+	"bytes"
 	"encoding/json"
+	"math"
+	"reflect"
 	"strconv"
 
 	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
@@ -36,6 +39,12 @@ type Bean interface {
 	Split(size int) []Bean
 }
 
+// NotLogged is the value of column absent in the row image written by server with binlog_row_image=MINIMAL or NOBLOB option.
+// Such columns are omitted from messages unlike columns with NULL value.
+var NotLogged any = notLogged{}
+
+type notLogged struct{}
+
 type DataMap map[string]any
 type DataSlice []DataMap
 
@@ -54,6 +63,12 @@ func (r Record) sizeBytes() int {
 }
 
 func (val DataMap) sizeBytes() int {
+	if val == nil {
+		return 4 // null
+	}
+	if len(val) == 0 {
+		return 2 // {}
+	}
 	size := 2            // {...}
 	size += len(val) - 1 // ...,...
 	for k, v := range val {
@@ -71,21 +86,61 @@ func jsonSize(value interface{}) int {
 	case uint, uint8, uint16, uint32, uint64:
 		return len(strconv.FormatUint(toUint64(val), 10))
 	case float32:
-		return len(strconv.FormatFloat(float64(val), 'g', -1, 32))
+		return floatSize(float64(val), 32)
 	case float64:
-		return len(strconv.FormatFloat(val, 'g', -1, 64))
+		return floatSize(val, 64)
+	case []float32:
+		size := 2 + max(len(val)-1, 0) // [v1,v2]
+		for _, v := range val {
+			size += floatSize(float64(v), 32)
+		}
+		return size
 	case string:
 		return len(strconv.Quote(val))
 	case Operation:
 		return len(strconv.Quote(string(val)))
 	case []byte:
 		return ((len(val)+2)/3)*4 + 2
+	case json.RawMessage:
+		return rawJSONSize(val)
+	case json.Number:
+		return len(val)
 	default:
 		b, _ := json.Marshal(val)
 		return len(b)
 	}
 }
 
+// floatSize returns size of the number formatted like json.Marshal does: exponent is used only for very small and large values.
+func floatSize(val float64, bits int) int {
+	abs := math.Abs(val)
+	if abs != 0 && (bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21)) {
+		text := strconv.FormatFloat(val, 'e', -1, bits)
+		// json.Marshal writes 1e-07 as 1e-7
+		if n := len(text); n >= 4 && text[n-4] == 'e' && text[n-3] == '-' && text[n-2] == '0' {
+			return n - 1
+		}
+		return len(text)
+	}
+	return len(strconv.FormatFloat(val, 'f', -1, bits))
+}
+
+// rawJSONSize returns size of embedded JSON document, json.Marshal escapes HTML characters and line separators in it.
+func rawJSONSize(val json.RawMessage) int {
+	if len(val) == 0 {
+		return 4 // null
+	}
+	size := len(val)
+	for _, b := range val {
+		if b == '<' || b == '>' || b == '&' {
+			size += 5 // \u00XX
+		}
+	}
+	size += 3 * bytes.Count(val, []byte("\u2028"))
+	size += 3 * bytes.Count(val, []byte("\u2029"))
+	return size
+}
+
 func toInt64(v any) int64 {
 	switch vv := v.(type) {
 	case int:
@@ -119,27 +174,47 @@ func toUint64(v any) uint64 {
 }
 
 func (ds DataSlice) sizeBytes() int {
-	size := len(ds) - 1 // ...,...
-	for _, val := range ds {
+	return sliceSize(ds)
+}
+
+func (ds DataSlice) split(baseSize int, maxSize int) []DataSlice {
+	parts := splitSlice(ds, baseSize, maxSize)
+	res := make([]DataSlice, len(parts))
+	for i, part := range parts {
+		res[i] = part
+	}
+	return res
+}
+
+type sizer interface {
+	sizeBytes() int
+}
+
+// sliceSize returns size of JSON array elements without brackets.
+func sliceSize[T sizer](items []T) int {
+	size := len(items) - 1 // ...,...
+	for _, val := range items {
 		size += val.sizeBytes()
 	}
 	return size
 }
 
-func (ds DataSlice) split(baseSize int, maxSize int) []DataSlice {
-	var res []DataSlice
+// splitSlice splits items into parts with size of baseSize plus elements size not exceeding maxSize,
+// a single element bigger than maxSize forms its own part.
+func splitSlice[T sizer](items []T, baseSize int, maxSize int) [][]T {
+	var res [][]T
 	var partSize int
-	var part DataSlice
-	for i, val := range ds {
+	var part []T
+	for i, val := range items {
 		valSize := val.sizeBytes()
 		if i == 0 {
 			partSize = baseSize + valSize
-			part = DataSlice{val}
+			part = []T{val}
 		} else {
 			if partSize+valSize+1 > maxSize {
 				res = append(res, part)
 				partSize = baseSize + valSize
-				part = DataSlice{val}
+				part = []T{val}
 			} else {
 				partSize += valSize + 1 // ...,...
 				part = append(part, val)
@@ -152,11 +227,7 @@ func (ds DataSlice) split(baseSize int, maxSize int) []DataSlice {
 func createValues(tableMetadata database.TableMetadata, rows [][]any) DataSlice {
 	result := make(DataSlice, len(rows))
 	for index, row := range rows {
-		values := DataMap{}
-		result[index] = values
-		for columnIndex, columnValue := range row {
-			values[tableMetadata[columnIndex]] = columnValue
-		}
+		result[index] = createDataMap(tableMetadata, row)
 	}
 	return result
 }
@@ -165,10 +236,7 @@ func createUpdatePairs(tableMetadata database.TableMetadata, rows [][]any) []Upd
 	result := make([]UpdatePair, len(rows)/2)
 	var pair UpdatePair = UpdatePair{}
 	for index, row := range rows {
-		values := DataMap{}
-		for columnIndex, columnValue := range row {
-			values[tableMetadata[columnIndex]] = columnValue
-		}
+		values := createDataMap(tableMetadata, row)
 		if index%2 == 0 {
 			pair.Before = values
 		} else {
@@ -179,3 +247,31 @@ func createUpdatePairs(tableMetadata database.TableMetadata, rows [][]any) []Upd
 	}
 	return result
 }
+
+// createDataMap maps column names to values, columns absent in the row image are omitted.
+func createDataMap(tableMetadata database.TableMetadata, row []any) DataMap {
+	values := DataMap{}
+	for columnIndex, columnValue := range row {
+		if columnValue == NotLogged {
+			continue
+		}
+		values[tableMetadata[columnIndex]] = columnValue
+	}
+	return values
+}
+
+// ChangedColumns returns indexes of columns with different values in before and after images of updated row.
+// Column absent in the after image isn't changed, column absent only in the before image is treated as changed.
+func ChangedColumns(before []any, after []any) []int {
+	var changed []int
+	for i, value := range after {
+		if value == NotLogged {
+			continue
+		}
+		if i < len(before) && before[i] != NotLogged && reflect.DeepEqual(before[i], value) {
+			continue
+		}
+		changed = append(changed, i)
+	}
+	return changed
+}
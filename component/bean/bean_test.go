@@ -17,7 +17,9 @@
 package bean_test
 
 import (
+	"encoding/json"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,7 +63,7 @@ func TestSizeBytesVsSerialized(t *testing.T) {
 			newBean: func() bean.Bean {
 				schema := randString()
 				table := randString()
-				fields, rows := randRows()
+				fields, rows := randSplittableRows()
 				return bean.NewInsert(schema, table, fields, rows)
 			},
 		},
@@ -70,10 +72,28 @@ func TestSizeBytesVsSerialized(t *testing.T) {
 			newBean: func() bean.Bean {
 				schema := randString()
 				table := randString()
-				fields, rows := randRows()
+				fields, rows := randSplittableRows()
 				return bean.NewDelete(schema, table, fields, rows)
 			},
 		},
+		{
+			name: "update",
+			newBean: func() bean.Bean {
+				schema := randString()
+				table := randString()
+				fields, rows := randUpdateRows()
+				return bean.NewUpdate(schema, table, fields, rows)
+			},
+		},
+		{
+			name: "update diff",
+			newBean: func() bean.Bean {
+				schema := randString()
+				table := randString()
+				fields, rows := randUpdateRows()
+				return bean.NewUpdateDiff(schema, table, fields, rows, fields[:1], seededRand.Intn(2) == 0)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -137,12 +157,22 @@ func TestSplittable(t *testing.T) {
 			},
 			splittable: false,
 		},
+		{
+			name: "splittable update",
+			newBean: func() bean.Bean {
+				schema := randString()
+				table := randString()
+				fields, rows := randUpdateRows()
+				return bean.NewUpdate(schema, table, fields, rows)
+			},
+			splittable: true,
+		},
 		{
 			name: "not splittable update",
 			newBean: func() bean.Bean {
 				schema := randString()
 				table := randString()
-				fields, rows := randRows()
+				fields, rows := randRowsM(randIntM(minWidth, maxWidth), 2)
 				return bean.NewUpdate(schema, table, fields, rows)
 			},
 			splittable: false,
@@ -224,8 +254,87 @@ func randRowsM(width, height int) ([]string, [][]any) {
 	return fields, rows
 }
 
-func randRows() ([]string, [][]any) {
+// randSplittableRows returns at least two rows, beans with a single row aren't splittable and have zero size.
+func randSplittableRows() ([]string, [][]any) {
 	width := randIntM(minWidth, maxWidth)
-	height := randIntM(minHeight, maxHeight)
+	height := randIntM(minHeight+1, maxHeight)
 	return randRowsM(width, height)
 }
+
+// randUpdateRows returns at least two pairs of before and after images.
+func randUpdateRows() ([]string, [][]any) {
+	width := randIntM(minWidth, maxWidth)
+	height := randIntM(minHeight+1, maxHeight)
+	return randRowsM(width, 2*height)
+}
+
+func TestSizeBytesOfEmbeddedJSON(t *testing.T) {
+	fields := []string{"id", "doc"}
+	rows := [][]any{
+		{int64(1), json.RawMessage(`{"key":"value","nested":{"list":[1,2.5,null,true]}}`)},
+		{int64(2), json.RawMessage(`{"html":"<a href=\"x\">&</a>","separator":"` + "\u2028" + `"}`)},
+		{int64(3), json.RawMessage(nil)},
+	}
+	for _, b := range []bean.Bean{
+		bean.NewInsert("schema", "table", fields, rows),
+		bean.NewDelete("schema", "table", fields, rows),
+	} {
+		size := b.SizeBytes()
+		data, err := b.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size < len(data) {
+			t.Fatalf("size calculated: %d, serialized: %d, data: %s", size, len(data), string(data))
+		}
+		if !strings.Contains(string(data), `"nested":{"list":[1,2.5,null,true]}`) {
+			t.Fatalf("embedded document expected, got %s", string(data))
+		}
+	}
+}
+
+func TestSizeBytesOfFloats(t *testing.T) {
+	fields := []string{"id", "embedding", "ratio"}
+	rows := [][]any{
+		{int64(1), []float32{1e6, -0.5, 1e-7, 3.4e38, 0}, float64(1e20)},
+		{int64(2), []float32{}, float64(1e-7)},
+		{int64(3), []float32{123456.78}, float64(-2.5e21)},
+	}
+	b := bean.NewInsert("schema", "table", fields, rows)
+	size := b.SizeBytes()
+	data, err := b.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size < len(data) {
+		t.Fatalf("size calculated: %d, serialized: %d, data: %s", size, len(data), string(data))
+	}
+	if !strings.Contains(string(data), `[1000000,-0.5,1e-7,3.4e+38,0]`) {
+		t.Fatalf("float array expected, got %s", string(data))
+	}
+}
+
+func TestNotLoggedColumns(t *testing.T) {
+	fields := []string{"id", "status", "note"}
+	rows := [][]any{
+		{int64(1), bean.NotLogged, nil},
+		{bean.NotLogged, "DONE", bean.NotLogged},
+	}
+	update := bean.NewUpdate("schema", "table", fields, rows)
+	data, err := update.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Schema":"schema","Table":"table","Operation":"UPDATE","Updated":[{"Before":{"id":1,"note":null},"After":{"status":"DONE"}}]}`
+	if string(data) != expected {
+		t.Fatalf("%s expected, got %s", expected, string(data))
+	}
+	insert := bean.NewInsert("schema", "table", fields, rows)
+	data, err = insert.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := insert.SizeBytes(); size < len(data) {
+		t.Fatalf("size calculated: %d, serialized: %d, data: %s", size, len(data), string(data))
+	}
+}
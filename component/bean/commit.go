@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 Exactpro (Exactpro Systems Limited)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+)
+
+const (
+	commitOperation Operation = "COMMIT"
+)
+
+// Commit marks the end of transaction, it follows all messages of the transaction.
+type Commit struct {
+	Record
+	TransactionId string
+	Messages      int
+}
+
+func NewCommit(transactionId string, messages int) Commit {
+	return Commit{Record: Record{Operation: commitOperation}, TransactionId: transactionId, Messages: messages}
+}
+
+func (b Commit) SizeBytes() int {
+	return 0
+}
+
+func (b Commit) Serialize() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b Commit) Splittable() bool {
+	return false
+}
+
+func (b Commit) Split(size int) []Bean {
+	return []Bean{b}
+}
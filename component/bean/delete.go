@@ -56,7 +56,7 @@ func (b Delete) Split(size int) []Bean {
 	parts := b.Deleted.split(b.baseSize(), size)
 	res := make([]Bean, len(parts))
 	for i, part := range parts {
-		res[i] = Insert{Record: b.Record, Inserted: part}
+		res[i] = Delete{Record: b.Record, Deleted: part}
 	}
 
 	return res
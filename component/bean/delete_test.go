@@ -39,4 +39,9 @@ func TestDeleteSplit(t *testing.T) {
 	if len(parts) < 4 {
 		t.Fatalf("expected >= 4, got: %d", len(parts))
 	}
+	for _, part := range parts {
+		if _, ok := part.(bean.Delete); !ok {
+			t.Fatalf("delete part expected, got: %T", part)
+		}
+	}
 }
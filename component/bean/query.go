@@ -28,6 +28,16 @@ const (
 	unknownOperation     Operation = "UNKNOWN"
 )
 
+// ChangesSchema returns true for operations changing column layout of the table.
+func (o Operation) ChangesSchema() bool {
+	return o == createTableOperation || o == dropTableOperation || o == alterTableOperation
+}
+
+// DropsTable returns true for DROP TABLE operation.
+func (o Operation) DropsTable() bool {
+	return o == dropTableOperation
+}
+
 type Query struct {
 	Record
 	Query string
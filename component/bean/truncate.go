@@ -0,0 +1,174 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package bean
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+)
+
+// queryColumn is the name reported for truncated statement text of Query bean.
+const queryColumn = "Query"
+
+// ErrNotTruncatable is returned for beans without values which can be truncated.
+var ErrNotTruncatable = errors.New("message can't be truncated")
+
+// truncationTarget is a value of the column which can be truncated.
+type truncationTarget struct {
+	values DataMap
+	key    string
+	column string
+}
+
+// Truncate returns copy of the bean with the largest text, binary and JSON values cut until serialized bean fits the size
+// and sorted names of truncated columns. The passed bean isn't modified. Embedded JSON documents are truncated as strings.
+// Statement text of Query bean is truncated and reported as Query column.
+func Truncate(b Bean, size int) (Bean, []string, error) {
+	var targets []truncationTarget
+	// refresh rebuilds the bean from truncated values when they aren't stored in the bean maps
+	var refresh func() Bean
+	switch v := b.(type) {
+	case Query:
+		values := DataMap{queryColumn: v.Query}
+		targets = appendTargets(targets, values)
+		refresh = func() Bean {
+			v.Query = values[queryColumn].(string)
+			return v
+		}
+	case Insert:
+		v.Inserted, targets = cloneSlice(v.Inserted)
+		b = v
+	case Delete:
+		v.Deleted, targets = cloneSlice(v.Deleted)
+		b = v
+	case Update:
+		v.Updated = slices.Clone(v.Updated)
+		for i, pair := range v.Updated {
+			v.Updated[i] = UpdatePair{Before: maps.Clone(pair.Before), After: maps.Clone(pair.After)}
+			targets = appendTargets(targets, v.Updated[i].Before)
+			targets = appendTargets(targets, v.Updated[i].After)
+		}
+		b = v
+	case UpdateDiff:
+		v.Updated = slices.Clone(v.Updated)
+		for i, change := range v.Updated {
+			cloned := RowChange{Key: maps.Clone(change.Key), Changed: make(map[string]DataMap, len(change.Changed)), After: maps.Clone(change.After)}
+			targets = appendTargets(targets, cloned.Key)
+			for column, values := range change.Changed {
+				cloned.Changed[column] = maps.Clone(values)
+				for key := range values {
+					targets = append(targets, truncationTarget{values: cloned.Changed[column], key: key, column: column})
+				}
+			}
+			targets = appendTargets(targets, cloned.After)
+			v.Updated[i] = cloned
+		}
+		b = v
+	default:
+		return nil, nil, fmt.Errorf("%w: %T", ErrNotTruncatable, b)
+	}
+
+	truncated := make(map[string]struct{})
+	for {
+		if refresh != nil {
+			b = refresh()
+		}
+		data, err := b.Serialize()
+		if err != nil {
+			return nil, nil, err
+		}
+		excess := len(data) - size
+		if excess <= 0 {
+			return b, slices.Sorted(maps.Keys(truncated)), nil
+		}
+		target := largestTarget(targets)
+		if target == nil {
+			return nil, nil, fmt.Errorf("message is bigger than %d bytes limit by %d bytes after truncation of all values", size, excess)
+		}
+		target.values[target.key] = cut(target.values[target.key], excess)
+		truncated[target.column] = struct{}{}
+	}
+}
+
+func cloneSlice(ds DataSlice) (DataSlice, []truncationTarget) {
+	result := make(DataSlice, len(ds))
+	var targets []truncationTarget
+	for i, values := range ds {
+		result[i] = maps.Clone(values)
+		targets = appendTargets(targets, result[i])
+	}
+	return result, targets
+}
+
+func appendTargets(targets []truncationTarget, values DataMap) []truncationTarget {
+	for column := range values {
+		targets = append(targets, truncationTarget{values: values, key: column, column: column})
+	}
+	return targets
+}
+
+// largestTarget returns not empty value with the biggest serialized size or nil when there is no such value.
+func largestTarget(targets []truncationTarget) *truncationTarget {
+	var result *truncationTarget
+	var resultSize int
+	for i := range targets {
+		switch v := targets[i].values[targets[i].key].(type) {
+		case string:
+			if len(v) == 0 {
+				continue
+			}
+		case []byte:
+			if len(v) == 0 {
+				continue
+			}
+		case json.RawMessage:
+			if len(v) == 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		if size := jsonSize(targets[i].values[targets[i].key]); result == nil || size > resultSize {
+			result, resultSize = &targets[i], size
+		}
+	}
+	return result
+}
+
+// cut shortens the value to reduce its serialized size at least by excess bytes when it is possible.
+// Each byte of string takes at least one byte in JSON, each 3 bytes of binary value take 4 bytes in base64.
+func cut(value any, excess int) any {
+	switch v := value.(type) {
+	case string:
+		n := max(len(v)-excess, 0)
+		for n > 0 && !utf8.RuneStart(v[n]) {
+			n--
+		}
+		return v[:n]
+	case []byte:
+		n := max(len(v)-3*((excess+3)/4), 0)
+		return v[:n]
+	case json.RawMessage:
+		return cut(string(v), excess)
+	default:
+		return value
+	}
+}
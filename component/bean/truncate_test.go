@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 Exactpro (Exactpro Systems Limited)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+)
+
+func TestTruncate(t *testing.T) {
+	fields := []string{"id", "text", "data", "doc"}
+	rows := [][]any{
+		{int64(1), strings.Repeat("ж", 300), make([]byte, 600), json.RawMessage(`{"key":"` + strings.Repeat("v", 300) + `"}`)},
+		{int64(1), "short", []byte{1}, json.RawMessage(`{}`)},
+	}
+	for _, b := range []bean.Bean{
+		bean.NewInsert("schema", "table", fields, rows[:1]),
+		bean.NewDelete("schema", "table", fields, rows[:1]),
+		bean.NewUpdate("schema", "table", fields, rows),
+		bean.NewUpdateDiff("schema", "table", fields, rows, []string{"id"}, true),
+	} {
+		original, err := b.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		truncated, columns, err := bean.Truncate(b, 400)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := truncated.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) > 400 {
+			t.Fatalf("size <= 400 expected, got %d: %s", len(data), data)
+		}
+		if !reflect.DeepEqual(columns, []string{"data", "doc", "text"}) {
+			t.Fatalf("all big columns are expected to be truncated, got %v", columns)
+		}
+		if !json.Valid(data) || strings.Contains(string(data), "\ufffd") {
+			t.Fatalf("valid JSON with whole characters expected, got %s", data)
+		}
+		if after, _ := b.Serialize(); string(after) != string(original) {
+			t.Fatalf("source %T is modified", b)
+		}
+	}
+	if _, _, err := bean.Truncate(bean.NewInsert("schema", "table", fields, rows[:1]), 10); err == nil {
+		t.Fatal("error expected for the size smaller than the message without values")
+	}
+}
+
+func TestTruncateQuery(t *testing.T) {
+	query := bean.NewQuery("schema", "table", "CREATE TABLE t (id INT) COMMENT '"+strings.Repeat("c", 1000)+"'", "CREATE_TABLE")
+	truncated, columns, err := bean.Truncate(query, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := truncated.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 300 || !json.Valid(data) {
+		t.Fatalf("valid JSON of size <= 300 expected, got %d: %s", len(data), data)
+	}
+	if !reflect.DeepEqual(columns, []string{"Query"}) {
+		t.Fatalf("truncated query expected, got %v", columns)
+	}
+	text := truncated.(bean.Query).Query
+	if text == "" || !strings.HasPrefix(query.Query, text) {
+		t.Fatalf("prefix of the query expected, got '%s'", text)
+	}
+
+	if _, _, err := bean.Truncate(bean.NewCommit("binlog.000001:4", 1), 10); !errors.Is(err, bean.ErrNotTruncatable) {
+		t.Fatalf("not truncatable error expected, got %v", err)
+	}
+}
@@ -38,8 +38,15 @@ func NewUpdate(schema string, table string, fields []string, rows [][]any) Updat
 	return Update{Record: Record{Schema: schema, Table: table, Operation: updateOperation}, Updated: createUpdatePairs(fields, rows)}
 }
 
+func (p UpdatePair) sizeBytes() int {
+	return 2 + 9 + p.Before.sizeBytes() + 1 + 8 + p.After.sizeBytes() // {"Before":{...},"After":{...}}
+}
+
 func (b Update) SizeBytes() int {
-	return 0
+	if !b.Splittable() {
+		return 0
+	}
+	return b.baseSize() + sliceSize(b.Updated)
 }
 
 func (b Update) Serialize() ([]byte, error) {
@@ -47,9 +54,23 @@ func (b Update) Serialize() ([]byte, error) {
 }
 
 func (b Update) Splittable() bool {
-	return false
+	return len(b.Updated) > 1
 }
 
 func (b Update) Split(size int) []Bean {
-	return []Bean{b}
+	if !b.Splittable() {
+		return []Bean{b}
+	}
+
+	parts := splitSlice(b.Updated, b.baseSize(), size)
+	res := make([]Bean, len(parts))
+	for i, part := range parts {
+		res[i] = Update{Record: b.Record, Updated: part}
+	}
+
+	return res
+}
+
+func (b Update) baseSize() int {
+	return b.Record.sizeBytes() + 12 // "Updated":[...]
 }
@@ -0,0 +1,129 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package bean
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+// RowChange contains key and changed columns of updated row. Each changed column is an object with Before and After values,
+// Before value is absent when the column isn't written into the before image. Key is null when key columns are unknown.
+type RowChange struct {
+	Key     DataMap
+	Changed map[string]DataMap
+	After   DataMap `json:",omitempty"`
+}
+
+// UpdateDiff is the update with changed columns only.
+type UpdateDiff struct {
+	Record
+	Updated []RowChange
+}
+
+// NewUpdateDiff creates the update from pairs of before and after images. Key columns are taken from the before image,
+// the after image is used for key columns absent in the before image, nil key means that key columns are unknown.
+// The complete after image is included when includeAfter is true.
+func NewUpdateDiff(schema string, table string, fields []string, rows [][]any, key []string, includeAfter bool) UpdateDiff {
+	return UpdateDiff{
+		Record:  Record{Schema: schema, Table: table, Operation: updateOperation},
+		Updated: createRowChanges(fields, rows, key, includeAfter),
+	}
+}
+
+func (c RowChange) sizeBytes() int {
+	size := 2 + 6 + c.Key.sizeBytes() // {"Key":{...}}
+	size += 1 + 10 + 2                // ,"Changed":{...}
+	if len(c.Changed) != 0 {
+		size += len(c.Changed) - 1 // ...,...
+		for name, values := range c.Changed {
+			size += jsonSize(name) + 1 + values.sizeBytes() // "<name>":{...}
+		}
+	}
+	if len(c.After) != 0 {
+		size += 1 + 8 + c.After.sizeBytes() // ,"After":{...}
+	}
+	return size
+}
+
+func (b UpdateDiff) SizeBytes() int {
+	if !b.Splittable() {
+		return 0
+	}
+	return b.baseSize() + sliceSize(b.Updated)
+}
+
+func (b UpdateDiff) Serialize() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b UpdateDiff) Splittable() bool {
+	return len(b.Updated) > 1
+}
+
+func (b UpdateDiff) Split(size int) []Bean {
+	if !b.Splittable() {
+		return []Bean{b}
+	}
+
+	parts := splitSlice(b.Updated, b.baseSize(), size)
+	res := make([]Bean, len(parts))
+	for i, part := range parts {
+		res[i] = UpdateDiff{Record: b.Record, Updated: part}
+	}
+
+	return res
+}
+
+func (b UpdateDiff) baseSize() int {
+	return b.Record.sizeBytes() + 12 // "Updated":[...]
+}
+
+func createRowChanges(tableMetadata database.TableMetadata, rows [][]any, key []string, includeAfter bool) []RowChange {
+	result := make([]RowChange, 0, len(rows)/2)
+	for index := 0; index+1 < len(rows); index += 2 {
+		before, after := rows[index], rows[index+1]
+		change := RowChange{Changed: map[string]DataMap{}}
+		if key != nil {
+			change.Key = DataMap{}
+		}
+		for columnIndex, name := range tableMetadata {
+			if key == nil || !slices.Contains(key, name) {
+				continue
+			}
+			if columnIndex < len(before) && before[columnIndex] != NotLogged {
+				change.Key[name] = before[columnIndex]
+			} else if columnIndex < len(after) && after[columnIndex] != NotLogged {
+				change.Key[name] = after[columnIndex]
+			}
+		}
+		for _, columnIndex := range ChangedColumns(before, after) {
+			values := DataMap{"After": after[columnIndex]}
+			if columnIndex < len(before) && before[columnIndex] != NotLogged {
+				values["Before"] = before[columnIndex]
+			}
+			change.Changed[tableMetadata[columnIndex]] = values
+		}
+		if includeAfter {
+			change.After = createDataMap(tableMetadata, after)
+		}
+		result = append(result, change)
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 Exactpro (Exactpro Systems Limited)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean_test
+
+import (
+	"testing"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+)
+
+func TestUpdateDiff(t *testing.T) {
+	fields := []string{"id", "status", "note", "data"}
+	rows := [][]any{
+		{int64(1), "NEW", nil, []byte{1, 2}}, {int64(1), "DONE", "checked", []byte{1, 2}},
+		// binlog_row_image=MINIMAL
+		{int64(2), bean.NotLogged, bean.NotLogged, bean.NotLogged}, {bean.NotLogged, "DONE", bean.NotLogged, bean.NotLogged},
+	}
+	tests := []struct {
+		name         string
+		includeAfter bool
+		expected     string
+	}{
+		{
+			name: "changes",
+			expected: `{"Schema":"schema","Table":"table","Operation":"UPDATE","Updated":[` +
+				`{"Key":{"id":1},"Changed":{"note":{"After":"checked","Before":null},"status":{"After":"DONE","Before":"NEW"}}},` +
+				`{"Key":{"id":2},"Changed":{"status":{"After":"DONE"}}}]}`,
+		},
+		{
+			name:         "after image",
+			includeAfter: true,
+			expected: `{"Schema":"schema","Table":"table","Operation":"UPDATE","Updated":[` +
+				`{"Key":{"id":1},"Changed":{"note":{"After":"checked","Before":null},"status":{"After":"DONE","Before":"NEW"}},` +
+				`"After":{"data":"AQI=","id":1,"note":"checked","status":"DONE"}},` +
+				`{"Key":{"id":2},"Changed":{"status":{"After":"DONE"}},"After":{"status":"DONE"}}]}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := bean.NewUpdateDiff("schema", "table", fields, rows, []string{"id"}, tc.includeAfter).Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tc.expected {
+				t.Fatalf("%s expected, got %s", tc.expected, string(data))
+			}
+		})
+	}
+}
+
+func TestUpdateDiffUnknownKey(t *testing.T) {
+	fields := []string{"id", "status"}
+	rows := [][]any{{int64(1), "NEW"}, {int64(1), "DONE"}}
+	for _, tc := range []struct {
+		name     string
+		key      []string
+		expected string
+	}{
+		{name: "unknown key", key: nil, expected: `{"Key":null,"Changed":{"status":{"After":"DONE","Before":"NEW"}}}`},
+		{name: "table without key", key: []string{}, expected: `{"Key":{},"Changed":{"status":{"After":"DONE","Before":"NEW"}}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := bean.NewUpdateDiff("schema", "table", fields, rows, tc.key, false)
+			data, err := diff.Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := `{"Schema":"schema","Table":"table","Operation":"UPDATE","Updated":[` + tc.expected + `]}`
+			if string(data) != expected {
+				t.Fatalf("%s expected, got %s", expected, string(data))
+			}
+		})
+	}
+}
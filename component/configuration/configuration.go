@@ -16,18 +16,120 @@
 
 package configuration
 
+type TLS struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	SkipVerify bool
+}
+
 type Connection struct {
 	Host     string
 	Port     uint16
 	Username string
 	Password string
+	Flavor   string
+	ServerID uint32
+	GTIDMode bool
+	TLS      *TLS
 }
 
 type SchemasConf = map[string][]string
 
+type Reconnect struct {
+	MaxAttempts    int
+	InitialDelayMs uint64
+	MaxDelayMs     uint64
+	Multiplier     float64
+}
+
+type Transactions struct {
+	Enabled        bool
+	MaxBufferBytes int
+	SpillDir       string
+}
+
+type OversizedRows struct {
+	Policy string
+}
+
+type Start struct {
+	Policy    string
+	Name      string
+	Pos       uint32
+	GTIDSet   string
+	Timestamp string
+}
+
+type SchemaHistory struct {
+	File string
+}
+
+type ColumnRule struct {
+	Column      string
+	Action      string
+	Replacement string
+	KeepFirst   int
+	KeepLast    int
+	MaskChar    string
+	Salt        string
+	Length      int
+}
+
+type RowFilter struct {
+	Operations []string
+	Image      string
+	Expression string
+}
+
+type UpdateRules struct {
+	Format       string
+	Key          []string
+	IncludeAfter bool
+	Noise        []string
+}
+
+type TableRules struct {
+	Schema  string
+	Table   string
+	Columns []ColumnRule
+	Filters []RowFilter
+	Updates UpdateRules
+}
+
+type ColumnFormat struct {
+	Schema  string
+	Table   string
+	Column  string
+	Format  string
+	Charset string
+}
+
+type Values struct {
+	JSON     string
+	Decimal  string
+	Temporal string
+	Timezone string
+	ZeroDate string
+	Enum     string
+	Bit      string
+	Spatial  string
+	Vector   string
+	Columns  []ColumnFormat
+}
+
 type Configuration struct {
-	Connection Connection
-	Schemas    SchemasConf
-	Group      string
-	Alias      string
+	Connection    Connection
+	Schemas       SchemasConf
+	Exclude       SchemasConf
+	Rules         []TableRules
+	Values        Values
+	Reconnect     Reconnect
+	Transactions  Transactions
+	OversizedRows OversizedRows
+	Start         Start
+	History       SchemaHistory
+	Group         string
+	Alias         string
 }
@@ -0,0 +1,141 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+const (
+	fileColumn     = "file"
+	positionColumn = "position"
+	logNameColumn  = "log_name"
+	fileSizeColumn = "file_size"
+)
+
+type BinlogPosition struct {
+	Name string
+	Pos  uint32
+}
+
+type BinlogFile struct {
+	Name string
+	Size uint64
+}
+
+// LoadBinlogPosition returns the current position of the source server binlog.
+func LoadBinlogPosition(conn conf.Connection, tlsConfig *tls.Config) (BinlogPosition, error) {
+	db, err := openInformationSchema(conn, tlsConfig)
+	if err != nil {
+		return BinlogPosition{}, fmt.Errorf("open mysql db for getting binlog position failure: %w", err)
+	}
+	defer closeDb(db)
+
+	// SHOW BINARY LOG STATUS replaces SHOW MASTER STATUS since MySQL 8.2
+	rows, err := queryRows(db, "SHOW BINARY LOG STATUS", "SHOW MASTER STATUS", fileColumn, positionColumn)
+	if err != nil {
+		return BinlogPosition{}, err
+	}
+	if len(rows) == 0 {
+		return BinlogPosition{}, fmt.Errorf("binlog status is empty, binary logging is disabled")
+	}
+	pos, err := strconv.ParseUint(rows[0][1], 10, 32)
+	if err != nil {
+		return BinlogPosition{}, fmt.Errorf("parse binlog position '%s' failure: %w", rows[0][1], err)
+	}
+	return BinlogPosition{Name: rows[0][0], Pos: uint32(pos)}, nil
+}
+
+// LoadBinlogFiles returns binlog files available on the source server in the creation order.
+func LoadBinlogFiles(conn conf.Connection, tlsConfig *tls.Config) ([]BinlogFile, error) {
+	db, err := openInformationSchema(conn, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("open mysql db for getting binlog files failure: %w", err)
+	}
+	defer closeDb(db)
+
+	rows, err := queryRows(db, "SHOW BINARY LOGS", "SHOW MASTER LOGS", logNameColumn, fileSizeColumn)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]BinlogFile, 0, len(rows))
+	for _, row := range rows {
+		size, err := strconv.ParseUint(row[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse binlog file size '%s' failure: %w", row[1], err)
+		}
+		files = append(files, BinlogFile{Name: row[0], Size: size})
+	}
+	return files, nil
+}
+
+// queryRows executes the query, or the fallback query when the first one fails, and returns values of the required columns.
+func queryRows(db *sql.DB, query string, fallback string, required ...string) ([][]string, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		logger.Debug().Err(err).Str("query", query).Str("fallback", fallback).Msg("Query failure, try to use fallback query")
+		rows, err = db.Query(fallback)
+		if err != nil {
+			return nil, fmt.Errorf("execute '%s' query failure: %w", fallback, err)
+		}
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("getting columns of '%s' query failure: %w", query, err)
+	}
+	indexes := make([]int, len(required))
+	for i, name := range required {
+		indexes[i] = -1
+		for j, column := range columns {
+			if strings.ToLower(column) == name {
+				indexes[i] = j
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("'%s' column isn't found in '%s' query result %v", name, query, columns)
+		}
+	}
+
+	var result [][]string
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan '%s' query result failure: %w", query, err)
+		}
+		row := make([]string, len(indexes))
+		for i, index := range indexes {
+			row[i] = string(values[index])
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate '%s' query result failure: %w", query, err)
+	}
+	return result, nil
+}
@@ -17,17 +17,25 @@
 package database
 
 import (
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/th2-net/th2-common-go/pkg/log"
 	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
 )
 
+// tlsConfigName is the name of TLS configuration registered in the driver.
+const tlsConfigName = "th2-listener"
+
 var (
 	logger = log.ForComponent("db-meta-data")
+
+	ErrNoColumns = errors.New("table has no columns")
 )
 
 type TableMetadata []string
@@ -36,40 +44,89 @@ type SchemaMetadata map[string]TableMetadata
 
 type DbMetadata map[string]SchemaMetadata
 
-func LoadMetadata(host string, port uint16, username string, password string, schemas conf.SchemasConf) (DbMetadata, error) {
-	if len(schemas) == 0 {
-		return nil, errors.New("no one schema isn't configured for loading db metadata")
-	}
-	dataSourceName := fmt.Sprintf("%s:%s@tcp(%s:%d)/information_schema", username, password, host, port)
-	db, err := sql.Open("mysql", dataSourceName)
+// LoadMetadata loads column names of existing tables matched by the selector.
+func LoadMetadata(conn conf.Connection, tlsConfig *tls.Config, selector *TableSelector) (DbMetadata, error) {
+	db, err := openInformationSchema(conn, tlsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("open mysql db for getting information_schema data failure: %w", err)
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			logger.Warn().Msg("Metadata db connection closed ungracefully")
+	defer closeDb(db)
+
+	tables, err := loadTables(db)
+	if err != nil {
+		return nil, err
+	}
+	dbMetadata := make(DbMetadata)
+	for _, table := range tables {
+		if !selector.Matches(table.schema, table.name) {
+			continue
 		}
-	}()
-
-	dbMetadata := make(DbMetadata, len(schemas))
-	for schema, tables := range schemas {
-		schemaMetadata := make(SchemaMetadata, len(tables))
-		for _, table := range tables {
-			if _, ok := schemaMetadata[table]; ok {
-				continue
-			}
-			tableMetadata, err := loadFields(db, schema, table)
-			if err != nil {
-				return nil, err
-			}
-			schemaMetadata[table] = tableMetadata
+		tableMetadata, err := loadFields(db, table.schema, table.name)
+		if err != nil {
+			return nil, err
 		}
-		dbMetadata[schema] = schemaMetadata
+		dbMetadata.Add(table.schema, table.name, tableMetadata)
+	}
+	if len(dbMetadata) == 0 {
+		logger.Warn().Msg("No one existing table matches the schemas configuration, tables are observed after creation")
 	}
 
 	return dbMetadata, nil
 }
 
+type tableName struct {
+	schema string
+	name   string
+}
+
+func loadTables(db *sql.DB) ([]tableName, error) {
+	rows, err := db.Query(
+		"SELECT TABLE_SCHEMA, TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' " +
+			"AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("execute query for getting tables failure: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []tableName
+	for rows.Next() {
+		var table tableName
+		if err := rows.Scan(&table.schema, &table.name); err != nil {
+			return nil, fmt.Errorf("scan query result for getting tables failure: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate query result for getting tables failure: %w", err)
+	}
+	return tables, nil
+}
+
+// Add starts observing the table.
+func (metadata DbMetadata) Add(schema string, table string, fields []string) {
+	schemaMetadata, ok := metadata[schema]
+	if !ok {
+		schemaMetadata = make(SchemaMetadata)
+		metadata[schema] = schemaMetadata
+	}
+	schemaMetadata[table] = fields
+}
+
+// Contains returns true when the table is observed by the listener.
+func (metadata DbMetadata) Contains(schema string, table string) bool {
+	_, ok := metadata[schema][table]
+	return ok
+}
+
+// SetFields replaces column names of the observed table, nil fields mean that the table doesn't exist.
+func (metadata DbMetadata) SetFields(schema string, table string, fields []string) {
+	if !metadata.Contains(schema, table) {
+		return
+	}
+	metadata[schema][table] = fields
+}
+
 func (metadata DbMetadata) GetFields(schema string, table string) []string {
 	schemaMetadata, ok := metadata[schema]
 	if !ok {
@@ -82,6 +139,53 @@ func (metadata DbMetadata) GetFields(schema string, table string) []string {
 	return tableMetadata
 }
 
+// LoadFields reads the current column names of the table from information_schema.
+func LoadFields(conn conf.Connection, tlsConfig *tls.Config, schema string, table string) ([]string, error) {
+	db, err := openInformationSchema(conn, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("open mysql db for getting %s.%s table metadata failure: %w", schema, table, err)
+	}
+	defer closeDb(db)
+	return loadFields(db, schema, table)
+}
+
+func openInformationSchema(conn conf.Connection, tlsConfig *tls.Config) (*sql.DB, error) {
+	cfg, err := informationSchemaConfig(conn, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// informationSchemaConfig creates the driver configuration, TLS configuration is registered in the driver,
+// so DSN formatted from the configuration refers to it by tls parameter.
+func informationSchemaConfig(conn conf.Connection, tlsConfig *tls.Config) (*mysql.Config, error) {
+	cfg := mysql.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(conn.Host, strconv.Itoa(int(conn.Port)))
+	cfg.User = conn.Username
+	cfg.Passwd = conn.Password
+	cfg.DBName = "information_schema"
+	if tlsConfig != nil {
+		if err := mysql.RegisterTLSConfig(tlsConfigName, tlsConfig); err != nil {
+			return nil, fmt.Errorf("registering TLS config failure: %w", err)
+		}
+		cfg.TLSConfig = tlsConfigName
+		cfg.TLS = tlsConfig
+	}
+	return cfg, nil
+}
+
+func closeDb(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		logger.Warn().Msg("Metadata db connection closed ungracefully")
+	}
+}
+
 func loadFields(db *sql.DB, schema string, table string) ([]string, error) {
 	rows, err := db.Query(
 		"SELECT COLUMN_NAME FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
@@ -109,7 +213,7 @@ func loadFields(db *sql.DB, schema string, table string) ([]string, error) {
 	}
 
 	if len(fields) == 0 {
-		return nil, fmt.Errorf("loaded field names for %s.%s table failure", schema, table)
+		return nil, fmt.Errorf("loaded field names for %s.%s table failure: %w", schema, table, ErrNoColumns)
 	}
 
 	logger.Info().Strs("fields", fields).Msgf("Loaded field names for %s.%s table", schema, table)
@@ -0,0 +1,58 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+func TestInformationSchemaTLS(t *testing.T) {
+	conn := conf.Connection{Host: "db.example", Port: 3306, Username: "user", Password: "password"}
+	tlsConfig := &tls.Config{ServerName: "db.example", MinVersion: tls.VersionTLS12}
+
+	cfg, err := informationSchemaConfig(conn, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS != tlsConfig {
+		t.Fatalf("TLS config expected, got %v", cfg.TLS)
+	}
+	dsn := cfg.FormatDSN()
+	if !strings.Contains(dsn, "tls="+tlsConfigName) {
+		t.Fatalf("tls parameter expected in DSN, got %s", dsn)
+	}
+	parsed, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.TLS == nil || parsed.TLS.ServerName != "db.example" || parsed.TLS.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("registered TLS config expected for DSN %s, got %v", dsn, parsed.TLS)
+	}
+
+	cfg, err = informationSchemaConfig(conn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dsn := cfg.FormatDSN(); cfg.TLS != nil || strings.Contains(dsn, "tls=") {
+		t.Fatalf("TLS isn't expected, got DSN %s", dsn)
+	}
+}
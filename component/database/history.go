@@ -0,0 +1,267 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// SchemaVersion is column layout of the table which is valid since the binlog position.
+// Nil fields mean that the table is dropped.
+// GTIDSet is the set of transactions executed at the point including the GTID transaction, it keys the version in GTID mode
+// because binlog file and position of the same transaction differ between servers.
+// Approximate version is the layout loaded when replication started, it is unknown whether the layout was valid at the position.
+type SchemaVersion struct {
+	Name        string
+	Pos         uint32
+	GTID        string
+	GTIDSet     string
+	Approximate bool
+	Schema      string
+	Table       string
+	Fields      []string
+}
+
+type recordedVersion struct {
+	SchemaVersion
+	gtidSet mysql.GTIDSet
+}
+
+// SchemaHistory is a persistent store of table column layouts keyed by binlog position or GTID set.
+// Each version is appended to the file as a JSON line, so the history survives restarts of the listener.
+type SchemaHistory struct {
+	file     *os.File
+	flavor   string
+	versions map[string][]recordedVersion
+}
+
+// OpenSchemaHistory loads the history from the file, the file is created when it doesn't exist.
+// GTID sets of versions are parsed according to the server flavor.
+func OpenSchemaHistory(path string, flavor string) (*SchemaHistory, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening schema history file failure: %w", err)
+	}
+	history := &SchemaHistory{file: file, flavor: flavor, versions: make(map[string][]recordedVersion)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var version SchemaVersion
+		if err := json.Unmarshal(scanner.Bytes(), &version); err != nil {
+			// the last line can be written partially when the listener is killed
+			logger.Warn().Err(err).Str("file", path).Int("line", line).Msg("Schema history record is corrupted, skip it")
+			continue
+		}
+		history.add(version)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("reading schema history file failure: %w", err)
+	}
+	if err := terminateLine(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	logger.Info().Str("file", path).Int("records", line).Int("tables", len(history.versions)).Msg("Schema history is loaded")
+	return history, nil
+}
+
+// Effective returns the version of the table valid at the binlog point.
+// When the executed GTID set is known, the latest version contained in the set is taken,
+// versions without GTID set are looked up by binlog file and position otherwise.
+func (h *SchemaHistory) Effective(schema string, table string, name string, pos uint32, executed mysql.GTIDSet) (SchemaVersion, bool) {
+	versions := h.versions[tableKey(schema, table)]
+	if executed != nil {
+		var latest *recordedVersion
+		for i := range versions {
+			version := &versions[i]
+			if version.gtidSet == nil || !executed.Contain(version.gtidSet) {
+				continue
+			}
+			if latest == nil || version.gtidSet.Contain(latest.gtidSet) {
+				latest = version
+			}
+		}
+		if latest != nil {
+			return latest.SchemaVersion, true
+		}
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		version := versions[i]
+		if executed != nil && version.gtidSet != nil {
+			continue
+		}
+		if comparePositions(version.Name, version.Pos, name, pos) <= 0 {
+			return version.SchemaVersion, true
+		}
+	}
+	return SchemaVersion{}, false
+}
+
+// Contains returns true when at least one version of the table is recorded.
+func (h *SchemaHistory) Contains(schema string, table string) bool {
+	return len(h.versions[tableKey(schema, table)]) != 0
+}
+
+// Version returns the version recorded exactly by the transaction with the GTID or at the binlog position when GTID is empty.
+func (h *SchemaHistory) Version(schema string, table string, name string, pos uint32, gtid string) (SchemaVersion, bool) {
+	for _, version := range h.versions[tableKey(schema, table)] {
+		if sameVersion(version.SchemaVersion, name, pos, gtid) {
+			return version.SchemaVersion, true
+		}
+	}
+	return SchemaVersion{}, false
+}
+
+// Record persists the version, the version already recorded by the same transaction or at the same position is kept.
+func (h *SchemaHistory) Record(version SchemaVersion) error {
+	if _, ok := h.Version(version.Schema, version.Table, version.Name, version.Pos, version.GTID); ok {
+		return nil
+	}
+	data, err := json.Marshal(version)
+	if err != nil {
+		return fmt.Errorf("serialization schema version failure: %w", err)
+	}
+	if _, err := h.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing schema version failure: %w", err)
+	}
+	if err := h.file.Sync(); err != nil {
+		return fmt.Errorf("syncing schema history file failure: %w", err)
+	}
+	h.add(version)
+	logger.Info().Str("schema", version.Schema).Str("table", version.Table).Str("name", version.Name).Uint32("pos", version.Pos).
+		Str("gtid", version.GTID).Bool("approximate", version.Approximate).Strs("fields", version.Fields).Msg("Schema version is recorded")
+	return nil
+}
+
+func (h *SchemaHistory) Close() error {
+	return h.file.Close()
+}
+
+func (h *SchemaHistory) add(version SchemaVersion) {
+	key := tableKey(version.Schema, version.Table)
+	versions := h.versions[key]
+	for _, recorded := range versions {
+		if sameVersion(recorded.SchemaVersion, version.Name, version.Pos, version.GTID) {
+			return
+		}
+	}
+	recorded := recordedVersion{SchemaVersion: version}
+	if version.GTIDSet != "" {
+		gtidSet, err := mysql.ParseGTIDSet(h.flavor, version.GTIDSet)
+		if err != nil {
+			logger.Warn().Err(err).Str("schema", version.Schema).Str("table", version.Table).Str("gtid-set", version.GTIDSet).
+				Msg("GTID set of schema version has incorrect format, use binlog position")
+		} else {
+			recorded.gtidSet = gtidSet
+		}
+	}
+	i := sort.Search(len(versions), func(i int) bool {
+		return comparePositions(versions[i].Name, versions[i].Pos, version.Name, version.Pos) > 0
+	})
+	versions = append(versions, recordedVersion{})
+	copy(versions[i+1:], versions[i:])
+	versions[i] = recorded
+	h.versions[key] = versions
+}
+
+// sameVersion compares versions by GTID when both of them are recorded in GTID mode, by binlog position otherwise.
+func sameVersion(version SchemaVersion, name string, pos uint32, gtid string) bool {
+	if version.GTID != "" && gtid != "" {
+		return version.GTID == gtid
+	}
+	return comparePositions(version.Name, version.Pos, name, pos) == 0
+}
+
+// terminateLine completes the partially written record, so the next record starts from the new line.
+func terminateLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("getting schema history file info failure: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("reading schema history file failure: %w", err)
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	if _, err := file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("writing schema history file failure: %w", err)
+	}
+	return nil
+}
+
+func tableKey(schema string, table string) string {
+	return schema + "." + table
+}
+
+// comparePositions compares binlog positions, binlog files are ordered by numeric extension.
+func comparePositions(name1 string, pos1 uint32, name2 string, pos2 uint32) int {
+	if c := compareBinlogNames(name1, name2); c != 0 {
+		return c
+	}
+	switch {
+	case pos1 < pos2:
+		return -1
+	case pos1 > pos2:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBinlogNames(name1 string, name2 string) int {
+	base1, num1, ok1 := splitBinlogName(name1)
+	base2, num2, ok2 := splitBinlogName(name2)
+	if !ok1 || !ok2 || base1 != base2 {
+		return strings.Compare(name1, name2)
+	}
+	switch {
+	case num1 < num2:
+		return -1
+	case num1 > num2:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func splitBinlogName(name string) (string, uint64, bool) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", 0, false
+	}
+	num, err := strconv.ParseUint(name[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], num, true
+}
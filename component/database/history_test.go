@@ -0,0 +1,167 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+func TestSchemaHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := database.OpenSchemaHistory(path, mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions := []database.SchemaVersion{
+		{Name: "binlog.000009", Pos: 500, Schema: "db", Table: "t", Fields: []string{"id"}},
+		{Name: "binlog.000010", Pos: 200, Schema: "db", Table: "t", Fields: []string{"id", "name"}},
+		{Name: "binlog.000010", Pos: 900, Schema: "db", Table: "t"},
+		{Name: "binlog.000009", Pos: 700, Schema: "db", Table: "other", Fields: []string{"key"}},
+	}
+	for _, version := range versions {
+		if err := history.Record(version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the version recorded at the same position is kept
+	if err := history.Record(database.SchemaVersion{Name: "binlog.000010", Pos: 200, Schema: "db", Table: "t", Fields: []string{"current"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// partially written record
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"Name":"binlog.0000`); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err = database.OpenSchemaHistory(path, mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := history.Record(database.SchemaVersion{Name: "binlog.000011", Pos: 100, Schema: "db", Table: "t", Fields: []string{"id"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.Close(); err != nil {
+		t.Fatal(err)
+	}
+	history, err = database.OpenSchemaHistory(path, mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+
+	tests := []struct {
+		name   string
+		table  string
+		file   string
+		pos    uint32
+		found  bool
+		fields []string
+	}{
+		{name: "before the first version", table: "t", file: "binlog.000009", pos: 400},
+		{name: "the first version position", table: "t", file: "binlog.000009", pos: 500, found: true, fields: []string{"id"}},
+		{name: "the next file", table: "t", file: "binlog.000010", pos: 100, found: true, fields: []string{"id"}},
+		{name: "the second version", table: "t", file: "binlog.000010", pos: 300, found: true, fields: []string{"id", "name"}},
+		{name: "dropped table", table: "t", file: "binlog.000011", pos: 4, found: true},
+		{name: "recorded after corrupted record", table: "t", file: "binlog.000011", pos: 100, found: true, fields: []string{"id"}},
+		{name: "other table", table: "other", file: "binlog.000010", pos: 300, found: true, fields: []string{"key"}},
+		{name: "unknown table", table: "unknown", file: "binlog.000010", pos: 300},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			version, found := history.Effective("db", tc.table, tc.file, tc.pos, nil)
+			if found != tc.found || !slices.Equal(version.Fields, tc.fields) {
+				t.Fatalf("%v (%t) fields expected, got %v (%t)", tc.fields, tc.found, version.Fields, found)
+			}
+		})
+	}
+}
+
+func TestSchemaHistoryGTID(t *testing.T) {
+	const uuid = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	history, err := database.OpenSchemaHistory(filepath.Join(t.TempDir(), "history.jsonl"), mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+	versions := []database.SchemaVersion{
+		{Name: "binlog.000001", Pos: 4, GTIDSet: uuid + ":1-10", Approximate: true, Schema: "db", Table: "t", Fields: []string{"id"}},
+		{Name: "binlog.000001", Pos: 900, GTID: uuid + ":20", GTIDSet: uuid + ":1-20", Schema: "db", Table: "t", Fields: []string{"id", "name"}},
+		// the same transaction read from another server after failover
+		{Name: "mysql-bin.000007", Pos: 300, GTID: uuid + ":20", GTIDSet: uuid + ":1-20", Schema: "db", Table: "t", Fields: []string{"other"}},
+		{Name: "binlog.000001", Pos: 2000, GTID: uuid + ":30", GTIDSet: uuid + ":1-30", Schema: "db", Table: "t", Fields: []string{"id", "name", "created"}},
+		{Name: "binlog.000001", Pos: 100, Schema: "db", Table: "legacy", Fields: []string{"key"}},
+	}
+	for _, version := range versions {
+		if err := history.Record(version); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		table       string
+		file        string
+		pos         uint32
+		executed    string
+		found       bool
+		approximate bool
+		fields      []string
+	}{
+		{name: "before baseline", table: "t", file: "binlog.000001", pos: 4, executed: uuid + ":1-5"},
+		{name: "baseline", table: "t", file: "binlog.000001", pos: 500, executed: uuid + ":1-15", found: true, approximate: true, fields: []string{"id"}},
+		{name: "after DDL", table: "t", file: "binlog.000001", pos: 1000, executed: uuid + ":1-25", found: true, fields: []string{"id", "name"}},
+		{name: "position of another server", table: "t", file: "mysql-bin.000008", pos: 4, executed: uuid + ":1-21", found: true, fields: []string{"id", "name"}},
+		{name: "the last DDL", table: "t", file: "mysql-bin.000008", pos: 4, executed: uuid + ":1-40", found: true, fields: []string{"id", "name", "created"}},
+		{name: "version without GTID set", table: "legacy", file: "binlog.000001", pos: 200, executed: uuid + ":1-40", found: true, fields: []string{"key"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			executed, err := mysql.ParseGTIDSet(mysql.MySQLFlavor, tc.executed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			version, found := history.Effective("db", tc.table, tc.file, tc.pos, executed)
+			if found != tc.found || version.Approximate != tc.approximate || !slices.Equal(version.Fields, tc.fields) {
+				t.Fatalf("%v (%t, approximate %t) fields expected, got %v (%t, approximate %t)",
+					tc.fields, tc.found, tc.approximate, version.Fields, found, version.Approximate)
+			}
+		})
+	}
+
+	if version, ok := history.Version("db", "t", "mysql-bin.000007", 300, uuid+":20"); !ok || !slices.Equal(version.Fields, []string{"id", "name"}) {
+		t.Fatalf("version recorded by the transaction expected, got %v (%t)", version, ok)
+	}
+	if _, ok := history.Version("db", "t", "binlog.000001", 900, uuid+":21"); ok {
+		t.Fatal("version of other transaction at the same position isn't expected")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+const (
+	serverIdColumn = "server_id"
+	hostColumn     = "host"
+)
+
+type replica struct {
+	serverID uint32
+	host     string
+}
+
+// CheckServerID returns error when the source server or another replica registered on it uses the passed server id.
+// The replica registered with the passed report host is considered as previous session of the listener and is ignored.
+// Only the source server id is checked when the user isn't privileged to list replicas.
+func CheckServerID(conn conf.Connection, tlsConfig *tls.Config, serverID uint32, reportHost string) error {
+	db, err := openInformationSchema(conn, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("open mysql db for checking server id failure: %w", err)
+	}
+	defer closeDb(db)
+
+	var sourceID uint32
+	if err := db.QueryRow("SELECT @@server_id").Scan(&sourceID); err != nil {
+		return fmt.Errorf("getting source server id failure: %w", err)
+	}
+	replicas, err := loadReplicas(db)
+	if err != nil {
+		logger.Warn().Err(err).Uint32("server-id", serverID).
+			Msg("Replicas can't be listed, server id is checked against the source server only")
+	}
+	return checkServerID(sourceID, replicas, serverID, reportHost)
+}
+
+func checkServerID(sourceID uint32, replicas []replica, serverID uint32, reportHost string) error {
+	if sourceID == serverID {
+		return fmt.Errorf("server id %d is used by the source server", serverID)
+	}
+	for _, replica := range replicas {
+		if replica.serverID != serverID {
+			continue
+		}
+		if replica.host == reportHost {
+			logger.Warn().Uint32("server-id", serverID).Str("host", replica.host).
+				Msg("Replica with the same server id and report host is registered, it is considered as previous listener session")
+			continue
+		}
+		return fmt.Errorf("server id %d is used by the '%s' replica", serverID, replica.host)
+	}
+	logger.Info().Uint32("server-id", serverID).Int("replicas", len(replicas)).Msg("Server id is unique")
+	return nil
+}
+
+// loadReplicas executes SHOW REPLICAS query (MySQL 8.0.22+) and falls back to SHOW SLAVE HOSTS query for older servers and MariaDB.
+func loadReplicas(db *sql.DB) ([]replica, error) {
+	rows, err := queryRows(db, "SHOW REPLICAS", "SHOW SLAVE HOSTS", serverIdColumn, hostColumn)
+	if err != nil {
+		return nil, err
+	}
+	replicas := make([]replica, 0, len(rows))
+	for _, row := range rows {
+		serverID, err := strconv.ParseUint(row[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse replica server id '%s' failure: %w", row[0], err)
+		}
+		replicas = append(replicas, replica{serverID: uint32(serverID), host: row[1]})
+	}
+	return replicas, nil
+}
@@ -0,0 +1,46 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import "testing"
+
+func TestCheckServerID(t *testing.T) {
+	replicas := []replica{
+		{serverID: 2, host: "replica"},
+		{serverID: 1001, host: "th2:book/group/alias"},
+	}
+	tests := []struct {
+		name     string
+		serverID uint32
+		host     string
+		fail     bool
+	}{
+		{name: "unique", serverID: 1002, host: "th2:book/group/alias"},
+		{name: "source server", serverID: 1, host: "th2:book/group/alias", fail: true},
+		{name: "other replica", serverID: 2, host: "th2:book/group/alias", fail: true},
+		{name: "previous session", serverID: 1001, host: "th2:book/group/alias"},
+		{name: "other listener", serverID: 1001, host: "th2:book/group/other", fail: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkServerID(1, replicas, tc.serverID, tc.host)
+			if (err != nil) != tc.fail {
+				t.Fatalf("failure %t expected, got %v", tc.fail, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+// NameMatcher matches schema, table or column name by literal value, glob pattern (`*` and `?` wildcards)
+// or regular expression enclosed in slashes (`/tenant_\d+/`).
+type NameMatcher struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+func NewNameMatcher(pattern string) (NameMatcher, error) {
+	if pattern == "" {
+		return NameMatcher{}, errors.New("empty name pattern")
+	}
+	var expr string
+	switch {
+	case len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+		expr = pattern[1 : len(pattern)-1]
+	case strings.ContainsAny(pattern, "*?"):
+		expr = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern))
+	default:
+		return NameMatcher{pattern: pattern}, nil
+	}
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return NameMatcher{}, fmt.Errorf("compiling '%s' name pattern failure: %w", pattern, err)
+	}
+	return NameMatcher{pattern: pattern, regex: regex}, nil
+}
+
+func (m NameMatcher) Matches(name string) bool {
+	if m.regex == nil {
+		return m.pattern == name
+	}
+	return m.regex.MatchString(name)
+}
+
+type tableRule struct {
+	schema NameMatcher
+	tables []NameMatcher
+}
+
+func newTableRules(schemas conf.SchemasConf) ([]tableRule, error) {
+	rules := make([]tableRule, 0, len(schemas))
+	for schema, tables := range schemas {
+		schemaMatcher, err := NewNameMatcher(schema)
+		if err != nil {
+			return nil, err
+		}
+		rule := tableRule{schema: schemaMatcher, tables: make([]NameMatcher, 0, len(tables))}
+		for _, table := range tables {
+			tableMatcher, err := NewNameMatcher(table)
+			if err != nil {
+				return nil, fmt.Errorf("'%s' schema: %w", schema, err)
+			}
+			rule.tables = append(rule.tables, tableMatcher)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func matchRules(rules []tableRule, schema string, table string) bool {
+	for _, rule := range rules {
+		if !rule.schema.Matches(schema) {
+			continue
+		}
+		for _, tableMatcher := range rule.tables {
+			if tableMatcher.Matches(table) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TableSelector decides which tables are observed by the listener.
+// A table is observed when it matches any include rule and doesn't match any exclude rule.
+type TableSelector struct {
+	include []tableRule
+	exclude []tableRule
+}
+
+func NewTableSelector(include conf.SchemasConf, exclude conf.SchemasConf) (*TableSelector, error) {
+	if len(include) == 0 {
+		return nil, errors.New("no one schema isn't configured for observing")
+	}
+	includeRules, err := newTableRules(include)
+	if err != nil {
+		return nil, fmt.Errorf("creating include rules failure: %w", err)
+	}
+	excludeRules, err := newTableRules(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("creating exclude rules failure: %w", err)
+	}
+	return &TableSelector{include: includeRules, exclude: excludeRules}, nil
+}
+
+func (s *TableSelector) Matches(schema string, table string) bool {
+	return matchRules(s.include, schema, table) && !matchRules(s.exclude, schema, table)
+}
@@ -0,0 +1,90 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database_test
+
+import (
+	"testing"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+func TestTableSelector(t *testing.T) {
+	selector, err := database.NewTableSelector(
+		conf.SchemasConf{
+			"shop":        {"orders", "payments"},
+			"tenant_*":    {"accounts", "orders_20??????"},
+			`/audit_\d+/`: {`/events_(in|out)/`},
+			"with.dot":    {"table"},
+		},
+		conf.SchemasConf{
+			"tenant_000": {"*"},
+			"tenant_*":   {"orders_2024*"},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		schema  string
+		table   string
+		matches bool
+	}{
+		{schema: "shop", table: "orders", matches: true},
+		{schema: "shop", table: "payments", matches: true},
+		{schema: "shop", table: "customers", matches: false},
+		{schema: "shop_old", table: "orders", matches: false},
+		{schema: "tenant_001", table: "accounts", matches: true},
+		{schema: "tenant_400", table: "orders_20250123", matches: true},
+		{schema: "tenant_400", table: "orders_2025012", matches: false},
+		{schema: "tenant_400", table: "orders_20240123", matches: false},
+		{schema: "tenant_000", table: "accounts", matches: false},
+		{schema: "audit_12", table: "events_in", matches: true},
+		{schema: "audit_12", table: "events_inout", matches: false},
+		{schema: "audit_x", table: "events_in", matches: false},
+		{schema: "with.dot", table: "table", matches: true},
+		{schema: "withxdot", table: "table", matches: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.schema+"."+tc.table, func(t *testing.T) {
+			if matches := selector.Matches(tc.schema, tc.table); matches != tc.matches {
+				t.Fatalf("%t expected, got %t", tc.matches, matches)
+			}
+		})
+	}
+}
+
+func TestTableSelectorIncorrect(t *testing.T) {
+	tests := []struct {
+		name    string
+		include conf.SchemasConf
+		exclude conf.SchemasConf
+	}{
+		{name: "no schemas"},
+		{name: "incorrect regex", include: conf.SchemasConf{"/tenant_(/": {"orders"}}},
+		{name: "empty table", include: conf.SchemasConf{"shop": {""}}},
+		{name: "incorrect exclude regex", include: conf.SchemasConf{"shop": {"*"}}, exclude: conf.SchemasConf{"shop": {"/[/"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := database.NewTableSelector(tc.include, tc.exclude); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package database_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	serverHost = "localhost"
+)
+
+type certFiles struct {
+	cert string
+	key  string
+}
+
+type pki struct {
+	caFile      string
+	otherCaFile string
+	server      tls.Certificate
+	clientCAs   *x509.CertPool
+	client      certFiles
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	p := newPki(t)
+	addr := startServer(t, p)
+
+	tests := []struct {
+		name    string
+		tls     conf.TLS
+		success bool
+	}{
+		{
+			name:    "ca and client certificate",
+			tls:     conf.TLS{CAFile: p.caFile, CertFile: p.client.cert, KeyFile: p.client.key},
+			success: true,
+		},
+		{
+			name:    "without client certificate",
+			tls:     conf.TLS{CAFile: p.caFile},
+			success: false,
+		},
+		{
+			name:    "unknown ca",
+			tls:     conf.TLS{CAFile: p.otherCaFile, CertFile: p.client.cert, KeyFile: p.client.key},
+			success: false,
+		},
+		{
+			name:    "unknown ca with skip verify",
+			tls:     conf.TLS{CAFile: p.otherCaFile, CertFile: p.client.cert, KeyFile: p.client.key, SkipVerify: true},
+			success: true,
+		},
+		{
+			name:    "incorrect server name",
+			tls:     conf.TLS{CAFile: p.caFile, CertFile: p.client.cert, KeyFile: p.client.key, ServerName: "unknown.host"},
+			success: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := database.NewTLSConfig(&tc.tls, serverHost)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = handshake(addr, tlsConfig)
+			if tc.success && err != nil {
+				t.Fatalf("handshake expected success, got: %v", err)
+			}
+			if !tc.success && err == nil {
+				t.Fatal("handshake expected failure")
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigIncorrect(t *testing.T) {
+	p := newPki(t)
+
+	tests := []struct {
+		name string
+		tls  conf.TLS
+	}{
+		{
+			name: "cert without key",
+			tls:  conf.TLS{CertFile: p.client.cert},
+		},
+		{
+			name: "key without cert",
+			tls:  conf.TLS{KeyFile: p.client.key},
+		},
+		{
+			name: "missing ca file",
+			tls:  conf.TLS{CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		},
+		{
+			name: "ca file without certificates",
+			tls:  conf.TLS{CAFile: p.client.key},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := database.NewTLSConfig(&tc.tls, serverHost); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigDisabled(t *testing.T) {
+	tlsConfig, err := database.NewTLSConfig(nil, serverHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig != nil {
+		t.Fatalf("nil config expected, got: %v", tlsConfig)
+	}
+}
+
+func handshake(addr string, tlsConfig *tls.Config) error {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(time.Second)); err != nil {
+		return err
+	}
+	// Server rejects client certificate after client handshake is completed in TLS 1.3
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func startServer(t *testing.T, p pki) string {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{p.server},
+		ClientCAs:    p.clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				if err := conn.(*tls.Conn).Handshake(); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte{1})
+			}(conn)
+		}
+	}()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return net.JoinHostPort(serverHost, port)
+}
+
+func newPki(t *testing.T) pki {
+	dir := t.TempDir()
+	ca, caKey := newCertificate(t, "test ca", nil, nil, nil)
+	otherCa, _ := newCertificate(t, "other ca", nil, nil, nil)
+	server, serverKey := newCertificate(t, serverHost, ca, caKey, []string{serverHost})
+	client, clientKey := newCertificate(t, "th2", ca, caKey, nil)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	return pki{
+		caFile:      writePem(t, dir, "ca.pem", "CERTIFICATE", ca.Raw),
+		otherCaFile: writePem(t, dir, "other-ca.pem", "CERTIFICATE", otherCa.Raw),
+		server: tls.Certificate{
+			Certificate: [][]byte{server.Raw},
+			PrivateKey:  serverKey,
+		},
+		clientCAs: clientCAs,
+		client: certFiles{
+			cert: writePem(t, dir, "client.pem", "CERTIFICATE", client.Raw),
+			key:  writePem(t, dir, "client-key.pem", "EC PRIVATE KEY", marshalKey(t, clientKey)),
+		},
+	}
+}
+
+// newCertificate creates self-signed CA certificate when parent is nil.
+func newCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, dnsNames []string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     dnsNames,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	} else {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func marshalKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func writePem(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
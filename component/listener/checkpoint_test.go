@@ -0,0 +1,274 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	b "github.com/th2-net/th2-common-mq-batcher-go/pkg/batcher"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	testSchema = "test_schema"
+	testTable  = "test_table"
+	testEvent  = 100
+	testUUID   = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+)
+
+var errEndOfBinlog = errors.New("end of binlog")
+
+type sentMessage struct {
+	data     string
+	metadata map[string]string
+}
+
+type fakeBatcher struct {
+	messages []sentMessage
+}
+
+func (f *fakeBatcher) Send(data []byte, args b.MessageArguments) error {
+	f.messages = append(f.messages, sentMessage{data: string(data), metadata: args.Metadata})
+	return nil
+}
+
+func (f *fakeBatcher) Close() error {
+	return nil
+}
+
+type binlogFile struct {
+	name   string
+	events []*replication.BinlogEvent
+}
+
+// testBinlog builds binlog files with consecutive event positions.
+type testBinlog struct {
+	files     []*binlogFile
+	pos       uint32
+	row       int
+	timestamp uint32
+}
+
+func newTestBinlog() *testBinlog {
+	return &testBinlog{}
+}
+
+func (l *testBinlog) file(name string) *testBinlog {
+	if len(l.files) > 0 {
+		l.add(replication.ROTATE_EVENT, &replication.RotateEvent{NextLogName: []byte(name), Position: 4})
+	}
+	l.files = append(l.files, &binlogFile{name: name})
+	l.pos = 4
+	return l
+}
+
+// at sets time of the next events.
+func (l *testBinlog) at(timestamp time.Time) *testBinlog {
+	l.timestamp = uint32(timestamp.Unix())
+	return l
+}
+
+func (l *testBinlog) add(eventType replication.EventType, event replication.Event) *testBinlog {
+	f := l.files[len(l.files)-1]
+	l.pos += testEvent
+	f.events = append(f.events, &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: eventType, EventSize: testEvent, LogPos: l.pos, Timestamp: l.timestamp},
+		Event:  event,
+	})
+	return l
+}
+
+func (l *testBinlog) query(query string) *testBinlog {
+	return l.add(replication.QUERY_EVENT, &replication.QueryEvent{Schema: []byte(testSchema), Query: []byte(query)})
+}
+
+func (l *testBinlog) insert() *testBinlog {
+	l.row++
+	return l.add(replication.WRITE_ROWS_EVENTv2, &replication.RowsEvent{
+		Table:       &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)},
+		ColumnCount: 1,
+		Rows:        [][]any{{int64(l.row)}},
+	})
+}
+
+func (l *testBinlog) xid() *testBinlog {
+	return l.add(replication.XID_EVENT, &replication.XIDEvent{})
+}
+
+// gtid adds GTID event of testUUID server transaction.
+func (l *testBinlog) gtid(gno int64) *testBinlog {
+	return l.gtidCommitted(gno, time.Time{})
+}
+
+// gtidCommitted adds GTID event with immediate commit time, zero commit time isn't written like MySQL 5.7 does.
+func (l *testBinlog) gtidCommitted(gno int64, commit time.Time) *testBinlog {
+	sid, err := hex.DecodeString(strings.ReplaceAll(testUUID, "-", ""))
+	if err != nil {
+		panic(err)
+	}
+	event := &replication.GTIDEvent{SID: sid, GNO: gno}
+	if !commit.IsZero() {
+		event.ImmediateCommitTimestamp = uint64(commit.UnixMicro())
+	}
+	return l.add(replication.GTID_EVENT, event)
+}
+
+func (l *testBinlog) previousGTIDs(set string) *testBinlog {
+	return l.add(replication.PREVIOUS_GTIDS_EVENT, &replication.PreviousGTIDsEvent{GTIDSets: set})
+}
+
+// source streams events like server does: fake rotate event to the start file is followed by events starting from the position.
+func (l *testBinlog) source(st state) *testSource {
+	name, pos := st.position.Name, st.position.Pos
+	if name == "" {
+		name, pos = l.files[0].name, 4
+	}
+	events := []*replication.BinlogEvent{{
+		Header: &replication.EventHeader{EventType: replication.ROTATE_EVENT},
+		Event:  &replication.RotateEvent{NextLogName: []byte(name), Position: uint64(pos)},
+	}}
+	found := false
+	for _, f := range l.files {
+		if f.name == name {
+			found = true
+			for _, e := range f.events {
+				if e.Header.LogPos-e.Header.EventSize >= pos {
+					events = append(events, e)
+				}
+			}
+			continue
+		}
+		if found {
+			events = append(events, f.events...)
+		}
+	}
+	return &testSource{events: events}
+}
+
+type testSource struct {
+	events []*replication.BinlogEvent
+}
+
+func (s *testSource) GetEvent(_ context.Context) (*replication.BinlogEvent, error) {
+	if len(s.events) == 0 {
+		return nil, errEndOfBinlog
+	}
+	e := s.events[0]
+	s.events = s.events[1:]
+	return e, nil
+}
+
+func testListener(batcher *fakeBatcher, transactions bool) *Listener {
+	r := &Listener{
+		dbMetadata: database.DbMetadata{testSchema: {testTable: {"id"}}},
+		batcher:    batcher,
+		alias:      "test",
+		maxSize:    1024 * 1024,
+		loadFields: func(schema string, table string) ([]string, error) {
+			return []string{"id"}, nil
+		},
+	}
+	if transactions {
+		r.tx = newTransactionBuffer(0, "")
+	}
+	return r
+}
+
+func run(t *testing.T, binlog *testBinlog, transactions bool, st state) []sentMessage {
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, transactions)
+	if err := r.process(context.Background(), binlog.source(st), st); !errors.Is(err, errEndOfBinlog) {
+		t.Fatalf("end of binlog expected, got: %v", err)
+	}
+	return batcher.messages
+}
+
+func TestRestartFromCheckpoint(t *testing.T) {
+	// the biggest transaction produces 3 messages
+	const maxTransactionMessages = 3
+	binlog := newTestBinlog().
+		file("binlog.000001").
+		query("CREATE TABLE test_table (id INT)").
+		query("BEGIN").insert().insert().xid().
+		query("BEGIN").insert().query("COMMIT").
+		query("BEGIN").insert().query("ROLLBACK").
+		query("BEGIN").insert().
+		file("binlog.000002").
+		insert().insert().xid().
+		query("ALTER TABLE test_table ADD COLUMN name TEXT").
+		query("BEGIN").insert().xid()
+
+	for _, transactions := range []bool{false, true} {
+		t.Run(fmt.Sprintf("transactions %t", transactions), func(t *testing.T) {
+			all := run(t, binlog, transactions, state{})
+			if len(all) == 0 {
+				t.Fatal("messages expected")
+			}
+			for crash := 1; crash <= len(all); crash++ {
+				last := all[crash-1]
+				r := testListener(nil, transactions)
+				st := r.stateFromProperties(nil, last.metadata)
+				resumed := run(t, binlog, transactions, st)
+
+				// resumed messages must be the tail of all messages started not later than the crash point
+				start := len(all) - len(resumed)
+				if start < 0 || start > crash {
+					t.Fatalf("crash after %d message: %d messages are lost", crash, start-crash)
+				}
+				if crash-start > maxTransactionMessages {
+					t.Fatalf("crash after %d message: %d messages are duplicated", crash, crash-start)
+				}
+				for i, msg := range resumed {
+					if msg.data != all[start+i].data {
+						t.Fatalf("crash after %d message: message %d expected %s, got %s", crash, i, all[start+i].data, msg.data)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckpointProperties(t *testing.T) {
+	binlog := newTestBinlog().
+		file("binlog.000001").
+		query("BEGIN").insert().insert().xid()
+	messages := run(t, binlog, false, state{})
+	if len(messages) != 2 {
+		t.Fatalf("2 messages expected, got %d", len(messages))
+	}
+	for _, msg := range messages {
+		if msg.metadata[commitNameProp] != "binlog.000001" || msg.metadata[commitPosProp] != "4" {
+			t.Fatalf("checkpoint before transaction expected, got: %v", msg.metadata)
+		}
+		if !slices.Contains([]string{"204", "304"}, msg.metadata[logPosProp]) {
+			t.Fatalf("unexpected event position: %v", msg.metadata)
+		}
+	}
+	st := testListener(nil, false).stateFromProperties(nil, messages[1].metadata)
+	if st.position != (mysql.Position{Name: "binlog.000001", Pos: 4}) {
+		t.Fatalf("restart from transaction beginning expected, got: %s", st)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/th2-net/th2-common-go/pkg/queue/event"
+	utils "github.com/th2-net/th2-common-utils-go/pkg/event"
+	proto "github.com/th2-net/th2-grpc-common-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	eventType = "Info"
+)
+
+// eventBodyPart is a component of th2 event body.
+type eventBodyPart struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+type eventReporter struct {
+	router   event.Router
+	parentID *proto.EventID
+}
+
+// report sends th2 event with passed text lines as a child of the root event.
+func (r eventReporter) report(name string, status proto.EventStatus, lines ...string) {
+	if r.router == nil || r.parentID == nil {
+		return
+	}
+	body := make([]eventBodyPart, len(lines))
+	for i, line := range lines {
+		body[i] = eventBodyPart{Type: "message", Data: line}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		logger.Error().Err(err).Str("event", name).Msg("event body serialization failure")
+		return
+	}
+	id := utils.CreateEventID(r.parentID.BookName, r.parentID.Scope)
+	err = r.router.SendAll(utils.CreateEventBatch(r.parentID, &proto.Event{
+		Id:           id,
+		ParentId:     r.parentID,
+		EndTimestamp: timestamppb.Now(),
+		Status:       status,
+		Name:         name,
+		Type:         eventType,
+		Body:         data,
+	}))
+	if err != nil {
+		logger.Error().Err(err).Str("event", name).Msg("sending event failure")
+	}
+}
+
+func (r eventReporter) reportError(name string, err error, lines ...string) {
+	r.report(name, proto.EventStatus_FAILED, append(lines, fmt.Sprintf("Error: %v", err))...)
+}
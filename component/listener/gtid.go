@@ -0,0 +1,112 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"fmt"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// gtidTracker keeps the set of transactions which are completely read from binlog.
+// The transaction in progress isn't included into the executed set until the next one begins,
+// so the listener resumed from the executed set reads the transaction in progress again.
+type gtidTracker struct {
+	flavor   string
+	executed mysql.GTIDSet
+	current  string
+}
+
+func newGtidTracker(flavor string, executed mysql.GTIDSet) *gtidTracker {
+	tracker := &gtidTracker{flavor: flavor}
+	if executed != nil {
+		tracker.executed = executed.Clone()
+	}
+	return tracker
+}
+
+// begin marks the previous transaction as executed and remembers GTID of the next one.
+func (t *gtidTracker) begin(gtid string) error {
+	if err := t.commit(); err != nil {
+		return err
+	}
+	t.current = gtid
+	return nil
+}
+
+// commit adds the transaction in progress to the executed set.
+func (t *gtidTracker) commit() error {
+	current := t.current
+	t.current = ""
+	if current == "" || t.executed == nil {
+		return nil
+	}
+	if err := t.executed.Update(current); err != nil {
+		return fmt.Errorf("%w: updating executed GTID set by '%s' failure: %w", errInvalidEvent, current, err)
+	}
+	return nil
+}
+
+// merge adds GTID set from binlog file header (PREVIOUS_GTIDS_EVENT or MARIADB_GTID_LIST_EVENT) to the executed set.
+// The executed set can't be calculated until the tracker gets the first header when the listener is started by file and position.
+func (t *gtidTracker) merge(set string) error {
+	if t.executed != nil && set == "" {
+		return nil
+	}
+	if t.executed == nil {
+		executed, err := mysql.ParseGTIDSet(t.flavor, set)
+		if err != nil {
+			return fmt.Errorf("%w: parsing '%s' GTID set failure: %w", errInvalidEvent, set, err)
+		}
+		t.executed = executed
+		return nil
+	}
+	if err := t.executed.Update(set); err != nil {
+		return fmt.Errorf("%w: merging '%s' GTID set failure: %w", errInvalidEvent, set, err)
+	}
+	return nil
+}
+
+// set returns the executed GTID set or nil if it is unknown yet.
+func (t *gtidTracker) set() mysql.GTIDSet {
+	if t == nil {
+		return nil
+	}
+	return t.executed
+}
+
+// including returns copy of the executed GTID set with the transaction in progress or empty string if the set is unknown yet.
+func (t *gtidTracker) including(gtid string) (string, error) {
+	if t == nil || t.executed == nil {
+		return "", nil
+	}
+	set := t.executed.Clone()
+	if gtid != "" {
+		if err := set.Update(gtid); err != nil {
+			return "", fmt.Errorf("%w: updating GTID set by '%s' failure: %w", errInvalidEvent, gtid, err)
+		}
+	}
+	return set.String(), nil
+}
+
+// String returns the executed GTID set or empty string if it is unknown yet.
+func (t *gtidTracker) String() string {
+	if t == nil || t.executed == nil {
+		return ""
+	}
+	return t.executed.String()
+}
@@ -0,0 +1,133 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+func TestGtidTracker(t *testing.T) {
+	tracker := newGtidTracker(mysql.MySQLFlavor, nil)
+	check := func(expected string) {
+		t.Helper()
+		if actual := tracker.String(); actual != expected {
+			t.Fatalf("'%s' executed set expected, got '%s'", expected, actual)
+		}
+	}
+	// the set is unknown until binlog file header
+	if err := tracker.begin(testUUID + ":5"); err != nil {
+		t.Fatal(err)
+	}
+	check("")
+	if err := tracker.merge(testUUID + ":1-4"); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-4")
+	// the transaction in progress is added when the next one begins
+	if err := tracker.begin(testUUID + ":6"); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-5")
+	if err := tracker.commit(); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-6")
+	if err := tracker.merge(""); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-6")
+	if err := tracker.merge(testUUID + ":1-10"); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-10")
+	if err := tracker.begin("incorrect"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.commit(); err == nil {
+		t.Fatal("error expected for incorrect GTID")
+	}
+
+	// the initial set is copied
+	initial, err := mysql.ParseGTIDSet(mysql.MySQLFlavor, testUUID+":1-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker = newGtidTracker(mysql.MySQLFlavor, initial)
+	if err := tracker.begin(testUUID + ":4"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.commit(); err != nil {
+		t.Fatal(err)
+	}
+	check(testUUID + ":1-4")
+	if initial.String() != testUUID+":1-3" {
+		t.Fatalf("initial set isn't expected to be modified, got %s", initial)
+	}
+}
+
+func TestGtidSetProperty(t *testing.T) {
+	process := func(binlog *testBinlog, st state) []sentMessage {
+		t.Helper()
+		batcher := &fakeBatcher{}
+		r := testListener(batcher, false)
+		r.conf.GTIDMode = true
+		r.flavor = mysql.MySQLFlavor
+		if err := r.process(context.Background(), binlog.source(state{}), st); !errors.Is(err, errEndOfBinlog) {
+			t.Fatalf("end of binlog expected, got: %v", err)
+		}
+		return batcher.messages
+	}
+	check := func(messages []sentMessage, expected ...[2]string) {
+		t.Helper()
+		if len(messages) != len(expected) {
+			t.Fatalf("%d messages expected, got %d", len(expected), len(messages))
+		}
+		for i, msg := range messages {
+			if msg.metadata[gtidProp] != expected[i][0] || msg.metadata[gtidSetProp] != expected[i][1] {
+				t.Fatalf("message %d: %s gtid and %s gtid-set expected, got %v", i, expected[i][0], expected[i][1], msg.metadata)
+			}
+		}
+	}
+
+	binlog := newTestBinlog().
+		file("binlog.000001").
+		previousGTIDs(testUUID + ":1-5").
+		gtid(6).query("BEGIN").insert().xid().
+		gtid(7).query("BEGIN").insert().xid()
+	messages := process(binlog, state{})
+	// the set lags by one transaction, so the transaction of the message is read again after restart
+	check(messages, [2]string{testUUID + ":6", testUUID + ":1-5"}, [2]string{testUUID + ":7", testUUID + ":1-6"})
+
+	r := testListener(nil, false)
+	r.conf.GTIDMode = true
+	r.flavor = mysql.MySQLFlavor
+	st := r.stateFromProperties(nil, messages[1].metadata)
+	if st.gtidSet == nil || st.gtidSet.String() != testUUID+":1-6" {
+		t.Fatalf("state with GTID set expected, got %s", st)
+	}
+
+	// server streams transactions absent in the set
+	resumed := newTestBinlog().
+		file("binlog.000001").
+		gtid(7).query("BEGIN").insert().xid().
+		gtid(8).query("BEGIN").insert().xid()
+	check(process(resumed, st), [2]string{testUUID + ":7", testUUID + ":1-6"}, [2]string{testUUID + ":8", testUUID + ":1-7"})
+}
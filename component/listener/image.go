@@ -0,0 +1,78 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+)
+
+const (
+	fullRowImage    = "FULL"
+	noBlobRowImage  = "NOBLOB"
+	minimalRowImage = "MINIMAL"
+)
+
+// rowImage returns row image of the event by columns absent in its rows. Server doesn't write binlog_row_image option value,
+// so the image with only BLOB, TEXT, JSON, spatial and VECTOR columns absent is reported as NOBLOB one.
+// MINIMAL image can look like FULL or NOBLOB one when all columns or only long data columns are absent.
+func rowImage(rowsEvent *replication.RowsEvent) string {
+	image := fullRowImage
+	for _, skipped := range rowsEvent.SkippedColumns {
+		for _, column := range skipped {
+			if column >= len(rowsEvent.Table.ColumnType) {
+				return minimalRowImage
+			}
+			switch rowsEvent.Table.ColumnType[column] {
+			case mysql.MYSQL_TYPE_TINY_BLOB, mysql.MYSQL_TYPE_MEDIUM_BLOB, mysql.MYSQL_TYPE_LONG_BLOB, mysql.MYSQL_TYPE_BLOB,
+				mysql.MYSQL_TYPE_JSON, mysql.MYSQL_TYPE_GEOMETRY, mysql.MYSQL_TYPE_VECTOR:
+				image = noBlobRowImage
+			default:
+				return minimalRowImage
+			}
+		}
+	}
+	return image
+}
+
+// markNotLogged replaces values of columns absent in the row images by bean.NotLogged value,
+// go-mysql leaves them nil like values of NULL columns. The rows are copied when they have absent columns.
+func markNotLogged(rows [][]any, skippedColumns [][]int) [][]any {
+	var result [][]any
+	for i, skipped := range skippedColumns {
+		if len(skipped) == 0 || i >= len(rows) {
+			continue
+		}
+		if result == nil {
+			result = make([][]any, len(rows))
+			copy(result, rows)
+		}
+		row := make([]any, len(rows[i]))
+		copy(row, rows[i])
+		for _, column := range skipped {
+			if column < len(row) {
+				row[column] = bean.NotLogged
+			}
+		}
+		result[i] = row
+	}
+	if result == nil {
+		return rows
+	}
+	return result
+}
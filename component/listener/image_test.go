@@ -0,0 +1,61 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+)
+
+func TestRowImage(t *testing.T) {
+	table := &replication.TableMapEvent{
+		ColumnType: []byte{mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_VARCHAR, mysql.MYSQL_TYPE_BLOB, mysql.MYSQL_TYPE_JSON},
+	}
+	tests := []struct {
+		skipped  [][]int
+		expected string
+	}{
+		{skipped: [][]int{{}, {}}, expected: fullRowImage},
+		{skipped: [][]int{{2, 3}, {2}}, expected: noBlobRowImage},
+		{skipped: [][]int{{1, 2, 3}, {0}}, expected: minimalRowImage},
+	}
+	for _, tc := range tests {
+		event := &replication.RowsEvent{Table: table, SkippedColumns: tc.skipped}
+		if image := rowImage(event); image != tc.expected {
+			t.Fatalf("%s image expected for %v, got %s", tc.expected, tc.skipped, image)
+		}
+	}
+}
+
+func TestMarkNotLogged(t *testing.T) {
+	rows := [][]any{{int32(1), nil, nil}, {nil, "DONE", nil}}
+	if result := markNotLogged(rows, [][]int{{}, {}}); &result[0] != &rows[0] {
+		t.Fatal("rows without absent columns are expected to be kept")
+	}
+	result := markNotLogged(rows, [][]int{{1}, {0, 2}})
+	expected := [][]any{{int32(1), bean.NotLogged, nil}, {bean.NotLogged, "DONE", bean.NotLogged}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("%v expected, got %v", expected, result)
+	}
+	if rows[0][1] != nil || rows[1][0] != nil {
+		t.Fatalf("source rows are modified: %v", rows)
+	}
+}
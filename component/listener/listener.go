@@ -19,19 +19,25 @@ package listener
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-mysql-org/go-mysql/mysql"
 	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/th2-net/th2-common-go/pkg/log"
+	"github.com/th2-net/th2-common-go/pkg/queue/event"
 	b "github.com/th2-net/th2-common-mq-batcher-go/pkg/batcher"
 	proto "github.com/th2-net/th2-grpc-common-go"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component"
 	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
 	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
 	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/rules"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/values"
 	"github.com/th2-net/th2-lwdp-grpc-fetcher-go/pkg/fetcher"
 )
 
@@ -40,6 +46,17 @@ const (
 	logPosProp       = "pos"
 	logSeqNumProp    = "seq"
 	logTimestampProp = "timestamp"
+	gtidSetProp      = "gtid-set"
+	gtidProp         = "gtid"
+	originalTimeProp = "original-timestamp"
+	transactionProp  = "transaction-id"
+	commitNameProp   = "commit-name"
+	commitPosProp    = "commit-pos"
+	rowImageProp     = "row-image"
+
+	beginQuery    = "BEGIN"
+	commitQuery   = "COMMIT"
+	rollbackQuery = "ROLLBACK"
 
 	msgProtocol     = "json"
 	msgProtocolSize = len("json")
@@ -58,53 +75,185 @@ type newBean func(schema string, table string, fields []string, rows [][]any) be
 
 type Listener struct {
 	dbMetadata database.DbMetadata
+	selector   *database.TableSelector
+	rules      *rules.Rules
+	converter  *values.Converter
+	// ignored caches tables which don't match the selector
+	ignored    map[string]struct{}
 	batcher    b.MqBatcher[b.MessageArguments]
+	events     eventReporter
 	conf       conf.Connection
+	backoff    backoff
+	flavor     string
+	serverID   uint32
+	reportHost string
+	tlsConfig  *tls.Config
 	book       string
 	group      string
 	alias      string
 	maxSize    int
+	oversized  string
+	tx         *transactionBuffer
+	start      startPolicy
+	history    *database.SchemaHistory
+	// loadFields reads the current column names of the table
+	loadFields func(schema string, table string) ([]string, error)
+	// unknownKeys caches tables reported without primary key for diff format
+	unknownKeys map[string]struct{}
+
+	// lastSent is metadata of the last message handed to the batcher.
+	lastSent  map[string]string
+	sentCount uint64
 }
 
-func New(batcher b.MqBatcher[b.MessageArguments], conf conf.Connection, schemas conf.SchemasConf, book string, group string, alias string, maxSize int) (*Listener, error) {
-	dbMetadata, err := database.LoadMetadata(conf.Host, conf.Port, conf.Username, conf.Password, schemas)
+func New(batcher b.MqBatcher[b.MessageArguments], eventRouter event.Router, rootEventID *proto.EventID, cfg conf.Configuration, book string, group string, alias string, maxSize int) (*Listener, error) {
+	conf := cfg.Connection
+	flavor := component.OrDefaultIfEmpty(conf.Flavor, mysql.MySQLFlavor)
+	if flavor != mysql.MySQLFlavor && flavor != mysql.MariaDBFlavor {
+		return nil, fmt.Errorf("unknown '%s' flavor. known values ['%s','%s']", flavor, mysql.MySQLFlavor, mysql.MariaDBFlavor)
+	}
+	start, err := newStartPolicy(cfg.Start, flavor)
+	if err != nil {
+		return nil, fmt.Errorf("creating start policy failure: %w", err)
+	}
+	serverID := conf.ServerID
+	if serverID == 0 {
+		serverID = autoServerID(book, group, alias)
+		logger.Info().Uint32("server-id", serverID).Msg("Server id is generated")
+	}
+	tlsConfig, err := database.NewTLSConfig(conf.TLS, conf.Host)
+	if err != nil {
+		return nil, fmt.Errorf("creating TLS config failure: %w", err)
+	}
+	reportHost := reportHost(book, group, alias)
+	if err := database.CheckServerID(conf, tlsConfig, serverID, reportHost); err != nil {
+		return nil, fmt.Errorf("checking server id failure: %w", err)
+	}
+	selector, err := database.NewTableSelector(cfg.Schemas, cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("creating table selector failure: %w", err)
+	}
+	oversized, err := newOversizedPolicy(cfg.OversizedRows)
+	if err != nil {
+		return nil, fmt.Errorf("creating oversized rows policy failure: %w", err)
+	}
+	tableRules, err := rules.New(cfg.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("creating table rules failure: %w", err)
+	}
+	converter, err := values.New(cfg.Values)
+	if err != nil {
+		return nil, fmt.Errorf("creating value converter failure: %w", err)
+	}
+	dbMetadata, err := database.LoadMetadata(conf, tlsConfig, selector)
 	if err != nil {
 		return nil, fmt.Errorf("loading schema metadata ta failure: %w", err)
 	}
+	var history *database.SchemaHistory
+	if cfg.History.File != "" {
+		history, err = database.OpenSchemaHistory(cfg.History.File, flavor)
+		if err != nil {
+			return nil, fmt.Errorf("opening schema history failure: %w", err)
+		}
+	}
+	var tx *transactionBuffer
+	if cfg.Transactions.Enabled {
+		tx = newTransactionBuffer(cfg.Transactions.MaxBufferBytes, cfg.Transactions.SpillDir)
+	}
 	return &Listener{
 		dbMetadata: dbMetadata,
+		selector:   selector,
+		rules:      tableRules,
+		converter:  converter,
+		ignored:    make(map[string]struct{}),
+		events:     eventReporter{router: eventRouter, parentID: rootEventID},
 		conf:       conf,
+		backoff:    newBackoff(cfg.Reconnect),
+		flavor:     flavor,
+		serverID:   serverID,
+		reportHost: reportHost,
+		tlsConfig:  tlsConfig,
 		batcher:    batcher,
 		book:       book,
 		group:      group,
 		alias:      alias,
 		maxSize:    int(maxSize),
+		oversized:  oversized,
+		tx:         tx,
+		start:      start,
+		history:    history,
+		loadFields: func(schema string, table string) ([]string, error) {
+			return database.LoadFields(conf, tlsConfig, schema, table)
+		},
 	}, nil
 }
 
 func (r *Listener) Listen(ctx context.Context, lwdp fetcher.LwdpFetcher) error {
-	filename, pos, err := r.loadPreviousState(ctx, lwdp)
+	st, err := r.loadPreviousState(ctx, lwdp)
 	if err != nil {
 		return fmt.Errorf("getting the last grouped message failure: %w", err)
 	}
-	err = r.listen(ctx, filename, pos)
+	attempt := 0
+	for {
+		sentCount := r.sentCount
+		err = r.listenWithFallback(ctx, st)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if r.lastSent != nil {
+			st = r.stateFromProperties(nil, r.lastSent)
+		}
+		if !retryable(err) {
+			logger.Error().Err(err).Stringer("state", st).Msg("Event processing failure, reconnect doesn't help")
+			return err
+		}
+		if sentCount != r.sentCount {
+			attempt = 0
+		}
+		attempt++
+		if attempt > r.backoff.maxAttempts {
+			r.events.reportError("Reconnect attempts are exhausted", err, fmt.Sprintf("Attempts: %d", r.backoff.maxAttempts), "Last "+st.String())
+			return fmt.Errorf("reconnect attempts (%d) are exhausted: %w", r.backoff.maxAttempts, err)
+		}
+		delay := r.backoff.delay(attempt)
+		logger.Warn().Err(err).Int("attempt", attempt).Int("max-attempts", r.backoff.maxAttempts).
+			Dur("delay", delay).Stringer("state", st).Msg("Replication failure, try to reconnect")
+		reconnectTotal.WithLabelValues(r.alias).Inc()
+		r.events.reportError("Reconnect", err, fmt.Sprintf("Attempt: %d/%d", attempt, r.backoff.maxAttempts), fmt.Sprintf("Delay: %s", delay), "Restart from "+st.String())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for reconnect failure: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// listenWithFallback starts replication and retries it from the beginning of binlog when the start point is unavailable on the server.
+func (r *Listener) listenWithFallback(ctx context.Context, st state) error {
+	err := r.listen(ctx, st)
 	var mysqlErr *mysql.MyError
 	if errors.As(err, &mysqlErr) {
 		logger.Error().Err(mysqlErr).Msg("Mysql error")
 		if mysqlErr.Code == mysql1236 {
-			switch mysqlErr.Message {
-			case mysqlIncorrectBinfile:
+			filename, pos := st.position.Name, st.position.Pos
+			switch {
+			case st.gtidSet != nil:
+				logger.Warn().Stringer("gtid-set", st.gtidSet).
+					Msg("Replication GTID set incorrect, try to use empty parameters")
+				err = r.listen(ctx, state{})
+			case mysqlErr.Message == mysqlIncorrectBinfile:
 				logger.Warn().Str("filename", filename).
 					Msg("Replication binfile incorrect, try to use empty parameters")
-				err = r.listen(ctx, "", 0)
-			case mysqlIncorrectPosition:
+				err = r.listen(ctx, state{})
+			case mysqlErr.Message == mysqlIncorrectPosition:
 				logger.Warn().Str("filename", filename).Uint32("position", pos).
 					Msg("Replication binfile incorrect, try to use 0 position")
-				err = r.listen(ctx, filename, 0)
+				err = r.listen(ctx, state{position: mysql.Position{Name: filename, Pos: 0}})
 			default:
 				logger.Warn().Str("filename", filename).Uint32("position", pos).
 					Msg("Unknown mysql error message, try to use empty parameters")
-				err = r.listen(ctx, "", 0)
+				err = r.listen(ctx, state{})
 			}
 		}
 	}
@@ -112,30 +261,52 @@ func (r *Listener) Listen(ctx context.Context, lwdp fetcher.LwdpFetcher) error {
 	return err
 }
 
-func (r *Listener) listen(ctx context.Context, filename string, pos uint32) error {
-	cfg := replication.BinlogSyncerConfig{
-		ServerID: 100,
-		Flavor:   "mysql",
-		Host:     r.conf.Host,
-		Port:     r.conf.Port,
-		User:     r.conf.Username,
-		Password: r.conf.Password,
-	}
-	syncer := replication.NewBinlogSyncer(cfg)
-	streamer, err := syncer.StartSync(mysql.Position{Name: filename, Pos: pos})
+func (r *Listener) newSyncer() *replication.BinlogSyncer {
+	return replication.NewBinlogSyncer(r.syncerConfig())
+}
+
+func (r *Listener) syncerConfig() replication.BinlogSyncerConfig {
+	return replication.BinlogSyncerConfig{
+		ServerID:  r.serverID,
+		Flavor:    r.flavor,
+		Localhost: r.reportHost,
+		Host:      r.conf.Host,
+		Port:      r.conf.Port,
+		User:      r.conf.Username,
+		Password:  r.conf.Password,
+		TLSConfig: r.tlsConfig,
+		// TIMESTAMP values are converted to ISO format from UTC text, the host timezone is kept otherwise
+		TimestampStringLocation: r.converter.TimestampLocation(),
+	}
+}
+
+func (r *Listener) listen(ctx context.Context, st state) error {
+	syncer := r.newSyncer()
+	defer syncer.Close()
+	var streamer *replication.BinlogStreamer
+	var err error
+	if st.gtidSet != nil {
+		streamer, err = syncer.StartSyncGTID(st.gtidSet.Clone())
+	} else {
+		streamer, err = syncer.StartSync(st.position)
+	}
 	if err != nil {
 		return fmt.Errorf("starting sync binlog failure: %w", err)
 	}
+	return r.process(ctx, streamer, st)
+}
 
-	// or you can start a GTID replication like
-	// gtidSet, _ := mysql.ParseGTIDSet(mysql.MySQLFlavor, "de278ad0-2106-11e4-9f8e-6edd0ca20947:1-2")
-	// streamer, _ := syncer.StartSyncGTID(gtidSet)
-	// the mysql GTID set is like this "de278ad0-2106-11e4-9f8e-6edd0ca20947:1-2" and uses mysql.MySQLFlavor
-	// the mariadb GTID set is like this "0-1-100" and uses mysql.MariaDBFlavor
+// process reads events from the source and publishes messages until the first failure.
+func (r *Listener) process(ctx context.Context, source eventSource, st state) error {
+	if r.tx != nil {
+		// incomplete transaction is read again after reconnect
+		defer r.tx.discard()
+	}
 
-	var logName string
-	var logSeqNum int64
-	var logTimestamp time.Time
+	bs := &binlogState{logName: st.position.Name, commitName: st.position.Name, commitPos: st.position.Pos}
+	if r.conf.GTIDMode {
+		bs.gtids = newGtidTracker(r.flavor, st.gtidSet)
+	}
 
 	newInsert := func(schema string, table string, fields []string, rows [][]any) bean.Bean {
 		return bean.NewInsert(schema, table, fields, rows)
@@ -152,7 +323,7 @@ func (r *Listener) listen(ctx context.Context, filename string, pos uint32) erro
 			return fmt.Errorf("checking context err failure: %w", err)
 		}
 
-		e, err := streamer.GetEvent(ctx)
+		e, err := source.GetEvent(ctx)
 		if err != nil {
 			return fmt.Errorf("getting binlog event failure: %w", err)
 		}
@@ -161,95 +332,411 @@ func (r *Listener) listen(ctx context.Context, filename string, pos uint32) erro
 		// Dump event
 		eventType := e.Header.EventType
 		switch eventType {
-		case replication.QUERY_EVENT:
-			if err := r.processQueryEvent(e, logName, logSeqNum, logTimestamp); err != nil {
+		case replication.QUERY_EVENT,
+			replication.MARIADB_QUERY_COMPRESSED_EVENT:
+			if err := r.processQueryEvent(e, bs); err != nil {
 				return fmt.Errorf("processing query event failure: %w", err)
 			}
+			// a query outside of transaction is committed implicitly
+			if !bs.inTransaction {
+				bs.commit(e.Header)
+			}
 		case replication.WRITE_ROWS_EVENTv1,
-			replication.WRITE_ROWS_EVENTv2:
-			if err := r.processRowsEvent(e, logName, logSeqNum, logTimestamp, newInsert); err != nil {
+			replication.WRITE_ROWS_EVENTv2,
+			replication.MARIADB_WRITE_ROWS_COMPRESSED_EVENT_V1:
+			if err := r.processRowsEvent(e, bs, rules.Insert, newInsert); err != nil {
 				return fmt.Errorf("processing write event failure: %w", err)
 			}
 		case replication.UPDATE_ROWS_EVENTv1,
-			replication.UPDATE_ROWS_EVENTv2:
-			if err := r.processRowsEvent(e, logName, logSeqNum, logTimestamp, newUpdate); err != nil {
+			replication.UPDATE_ROWS_EVENTv2,
+			replication.PARTIAL_UPDATE_ROWS_EVENT,
+			replication.MARIADB_UPDATE_ROWS_COMPRESSED_EVENT_V1:
+			if err := r.processRowsEvent(e, bs, rules.Update, newUpdate); err != nil {
 				return fmt.Errorf("processing update event failure: %w", err)
 			}
 		case replication.DELETE_ROWS_EVENTv1,
-			replication.DELETE_ROWS_EVENTv2:
-			if err := r.processRowsEvent(e, logName, logSeqNum, logTimestamp, newDelete); err != nil {
+			replication.DELETE_ROWS_EVENTv2,
+			replication.MARIADB_DELETE_ROWS_COMPRESSED_EVENT_V1:
+			if err := r.processRowsEvent(e, bs, rules.Delete, newDelete); err != nil {
 				return fmt.Errorf("processing delete event failure: %w", err)
 			}
-		case replication.ANONYMOUS_GTID_EVENT:
+		case replication.ANONYMOUS_GTID_EVENT,
+			replication.GTID_EVENT:
 			event := e.Event.(*replication.GTIDEvent)
-			logSeqNum = event.SequenceNumber
-			logTimestamp = event.ImmediateCommitTime()
+			bs.logSeqNum = event.SequenceNumber
+			bs.logTimestamp = event.ImmediateCommitTime()
+			bs.originalTimestamp = event.OriginalCommitTime()
+			bs.gtid = ""
+			if eventType == replication.GTID_EVENT {
+				if err := bs.beginTransaction(event); err != nil {
+					return fmt.Errorf("processing gtid event failure: %w", err)
+				}
+			}
+		case replication.PREVIOUS_GTIDS_EVENT:
+			if bs.gtids != nil {
+				event := e.Event.(*replication.PreviousGTIDsEvent)
+				if err := bs.gtids.merge(event.GTIDSets); err != nil {
+					return fmt.Errorf("processing previous gtids event failure: %w", err)
+				}
+			}
+		case replication.MARIADB_GTID_EVENT:
+			event := e.Event.(*replication.MariadbGTIDEvent)
+			bs.logSeqNum = int64(event.GTID.SequenceNumber)
+			bs.logTimestamp = time.Unix(int64(e.Header.Timestamp), 0)
+			bs.originalTimestamp = time.Time{}
+			if err := bs.beginTransaction(event); err != nil {
+				return fmt.Errorf("processing mariadb gtid event failure: %w", err)
+			}
+			// MariaDB doesn't write BEGIN query, GTID event starts transaction instead
+			if !event.IsStandalone() {
+				bs.inTransaction = true
+				if r.tx != nil {
+					r.tx.begin(transactionID(bs, e.Header))
+				}
+			}
+		case replication.MARIADB_GTID_LIST_EVENT:
+			if bs.gtids != nil {
+				event := e.Event.(*replication.MariadbGTIDListEvent)
+				gtids := make([]string, 0, len(event.GTIDs))
+				for _, gtid := range event.GTIDs {
+					gtids = append(gtids, gtid.String())
+				}
+				if err := bs.gtids.merge(strings.Join(gtids, ",")); err != nil {
+					return fmt.Errorf("processing mariadb gtid list event failure: %w", err)
+				}
+			}
+		case replication.XID_EVENT:
+			bs.commit(e.Header)
+			if err := r.commitTransaction(e, bs); err != nil {
+				return fmt.Errorf("processing xid event failure: %w", err)
+			}
 		case replication.ROTATE_EVENT:
 			event := e.Event.(*replication.RotateEvent)
-			logName = string(event.NextLogName)
+			bs.rotate(string(event.NextLogName), uint32(event.Position))
+			if err := r.recordBaseline(bs, e.Header); err != nil {
+				return fmt.Errorf("processing rotate event failure: %w", err)
+			}
 		}
 	}
 }
 
 func (r *Listener) Close() error {
+	if r.history != nil {
+		return r.history.Close()
+	}
 	return nil
 }
 
-func (r *Listener) loadPreviousState(ctx context.Context, lwdp fetcher.LwdpFetcher) (string, uint32, error) {
-	ctx, cancel := context.WithTimeout(ctx, time.Duration(1)*time.Minute)
+func (r *Listener) loadPreviousState(ctx context.Context, lwdp fetcher.LwdpFetcher) (state, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, time.Duration(1)*time.Minute)
 	defer cancel()
-	msg, err := lwdp.GetLastGroupedMessage(ctx, r.book, r.group, r.alias, proto.Direction_FIRST, fetcher.LwdpBase64Format)
+	msg, err := lwdp.GetLastGroupedMessage(fetchCtx, r.book, r.group, r.alias, proto.Direction_FIRST, fetcher.LwdpBase64Format)
 	if err != nil {
-		return "", 0, err
+		return state{}, err
 	}
 	if msg == nil {
-		logger.Info().Str("book", r.book).Str("alias", r.alias).Msg("no previous messages")
-		return "", 0, nil
+		st, err := r.initialState(ctx)
+		if err != nil {
+			return state{}, err
+		}
+		logger.Info().Str("book", r.book).Str("alias", r.alias).Str("policy", r.start.policy).Stringer("state", st).Msg("no previous messages, start by policy")
+		return st, nil
+	}
+
+	st := r.stateFromProperties(msg.MessageId, msg.MessageProperties)
+	logger.Info().Any("message-id", msg.MessageId).Stringer("state", st).Msg("loaded previous state")
+	return st, nil
+}
+
+// stateFromProperties restores start point from message properties.
+func (r *Listener) stateFromProperties(messageID any, properties map[string]string) state {
+	if r.conf.GTIDMode {
+		if gtidSet, ok := properties[gtidSetProp]; ok {
+			set, err := mysql.ParseGTIDSet(r.flavor, gtidSet)
+			if err == nil {
+				return state{gtidSet: set}
+			}
+			logger.Warn().Any("message-id", messageID).Str("target", gtidSetProp).Str("value", gtidSet).Err(err).Msg("GTID set has incorrect format")
+		} else {
+			logger.Warn().Any("message-id", messageID).Any("properties", properties).Str("target", gtidSetProp).Msg("GTID set property isn't found, try to use binlog position")
+		}
+	}
+
+	incomplete := incompleteGroup(properties)
+	if incomplete {
+		logger.Info().Any("message-id", messageID).Str(eventIDProp, properties[eventIDProp]).
+			Msg("the last message is a part of incomplete split event, the event is published again")
+	}
+
+	if commitName, ok := properties[commitNameProp]; ok {
+		if commitPos, ok := properties[commitPosProp]; ok {
+			num, err := strconv.ParseUint(commitPos, 10, 32)
+			if err == nil {
+				return state{position: mysql.Position{Name: commitName, Pos: uint32(num)}}
+			}
+			logger.Warn().Any("message-id", messageID).Str("target", commitPosProp).Str("value", commitPos).Err(err).Msg("commit position has incorrect format, try to use binlog position")
+		}
 	}
 
-	logName, ok := msg.MessageProperties[logNameProp]
+	logName, ok := properties[logNameProp]
 	if !ok {
-		logger.Warn().Any("message-id", msg.MessageId).Any("properties", msg.MessageProperties).Str("target", logNameProp).Msg("required property isn't found")
-		return "", 0, nil
+		logger.Warn().Any("message-id", messageID).Any("properties", properties).Str("target", logNameProp).Msg("required property isn't found")
+		return state{}
 	}
-	logPos, ok := msg.MessageProperties[logPosProp]
+	logPos, ok := properties[logPosProp]
 	if !ok {
-		logger.Warn().Any("message-id", msg.MessageId).Any("properties", msg.MessageProperties).Str("target", logPosProp).Msg("required property isn't found")
-		return "", 0, nil
+		logger.Warn().Any("message-id", messageID).Any("properties", properties).Str("target", logPosProp).Msg("required property isn't found")
+		return state{}
 	}
 	num, err := strconv.ParseUint(logPos, 10, 32)
 	if err != nil {
-		logger.Warn().Any("message-id", msg.MessageId).Str("target", logPosProp).Str("value", logPos).Err(err).Msg("log position has incorrect format")
-		return logName, 0, nil
+		logger.Warn().Any("message-id", messageID).Str("target", logPosProp).Str("value", logPos).Err(err).Msg("log position has incorrect format")
+		return state{position: mysql.Position{Name: logName}}
 	}
-	logger.Info().Any("message-id", msg.MessageId).Str("log-name", logName).Uint64("log-pos", num).Msg("loaded previous state")
-	return logName, uint32(num), nil
+	if incomplete {
+		// the position points to the end of the event, so the rest parts would be lost
+		logger.Warn().Any("message-id", messageID).Str("target", logNameProp).Str("value", logName).
+			Msg("commit position isn't found for incomplete split event, restart from the beginning of binlog file")
+		return state{position: mysql.Position{Name: logName}}
+	}
+	return state{position: mysql.Position{Name: logName, Pos: uint32(num)}}
 }
 
-func (r *Listener) processRowsEvent(event *replication.BinlogEvent, logName string, logSeqNum int64, logTimestamp time.Time, createBean newBean) error {
+func (r *Listener) processRowsEvent(event *replication.BinlogEvent, bs *binlogState, operation rules.Operation, createBean newBean) error {
 	rowsEvent, ok := event.Event.(*replication.RowsEvent)
 	if !ok {
-		return fmt.Errorf("cast event failure")
+		return fmt.Errorf("%w: cast event failure", errInvalidEvent)
 	}
 	schema := string(rowsEvent.Table.Schema)
 	table := string(rowsEvent.Table.Table)
-	fields := r.dbMetadata.GetFields(schema, table)
-	if len(fields) == 0 {
+	if !r.observe(schema, table) {
 		logger.Trace().Str("schema", schema).Str("table", table).Msg("Event skipped")
 		return nil
 	}
-	bean := createBean(schema, table, fields, rowsEvent.Rows)
-	metadata := createMetadata(logName, event.Header.LogPos, logSeqNum, logTimestamp)
+	fields := r.tableFields(rowsEvent.Table, bs, event.Header)
+	if len(fields) != int(rowsEvent.ColumnCount) {
+		// column names from information_schema are stale when DDL event isn't processed yet
+		logger.Warn().Str("schema", schema).Str("table", table).Strs("fields", fields).Uint64("columns", rowsEvent.ColumnCount).
+			Msg("column count of rows event doesn't match the known metadata, reload it")
+		if err := r.refreshTable(schema, table, false); err != nil {
+			return err
+		}
+		fields = r.dbMetadata.GetFields(schema, table)
+		if len(fields) != int(rowsEvent.ColumnCount) {
+			return fmt.Errorf("%w: %s.%s rows event has %d columns, but the table metadata has %d columns %v",
+				errInvalidEvent, schema, table, rowsEvent.ColumnCount, len(fields), fields)
+		}
+	}
+	rows, err := r.converter.Convert(rowsEvent.Table, fields, rowsEvent.Rows)
+	if err != nil {
+		return fmt.Errorf("%w: converting %s.%s rows failure: %w", errInvalidEvent, schema, table, err)
+	}
+	rows = markNotLogged(rows, rowsEvent.SkippedColumns)
+	// filters are evaluated against values before rules, the position is advanced by the next events
+	rows, filtered, noise := r.rules.Filter(schema, table, operation, fields, rowsEvent.Table.ColumnType, rows)
+	if filtered != 0 {
+		filteredRowsTotal.WithLabelValues(r.alias, schema, table, operation.String()).Add(float64(filtered))
+	}
+	if noise != 0 {
+		noiseUpdatesTotal.WithLabelValues(r.alias, schema, table).Add(float64(noise))
+	}
+	if filtered != 0 || noise != 0 {
+		logger.Trace().Str("schema", schema).Str("table", table).Stringer("operation", operation).Int("filtered", filtered).
+			Int("noise", noise).Msg("Rows filtered")
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if operation == rules.Update {
+		if format := r.rules.UpdateFormat(schema, table); format.Diff {
+			key := r.keyColumns(rowsEvent.Table, fields, format.Key)
+			createBean = func(schema string, table string, fields []string, rows [][]any) bean.Bean {
+				return bean.NewUpdateDiff(schema, table, fields, rows, key, format.IncludeAfter)
+			}
+		}
+	}
+	// rules are applied before bean creation to keep its size estimation correct
+	fields, rows = r.rules.Apply(schema, table, fields, rows)
+	bean := createBean(schema, table, fields, rows)
+	metadata := r.createMetadata(bs, event.Header)
+	metadata[rowImageProp] = rowImage(rowsEvent)
 	return r.putToBatch(bean, metadata)
 }
 
-func (r *Listener) processQueryEvent(event *replication.BinlogEvent, logName string, logSeqNum int64, logTimestamp time.Time) error {
+// keyColumns returns the configured key columns or primary key columns of the table map event.
+// Nil is returned when the primary key isn't written because the server doesn't write full row metadata.
+func (r *Listener) keyColumns(tableMap *replication.TableMapEvent, fields []string, configured []string) []string {
+	if len(configured) != 0 {
+		return configured
+	}
+	if len(tableMap.ColumnName) == 0 {
+		key := string(tableMap.Schema) + "." + string(tableMap.Table)
+		if _, ok := r.unknownKeys[key]; !ok {
+			if r.unknownKeys == nil {
+				r.unknownKeys = make(map[string]struct{})
+			}
+			r.unknownKeys[key] = struct{}{}
+			logger.Warn().Str("schema", string(tableMap.Schema)).Str("table", string(tableMap.Table)).
+				Msg("primary key isn't written without binlog_row_metadata=FULL option, configure key columns of diff format")
+		}
+		return nil
+	}
+	key := make([]string, 0, len(tableMap.PrimaryKey))
+	for _, index := range tableMap.PrimaryKey {
+		if index < uint64(len(fields)) {
+			key = append(key, fields[index])
+		}
+	}
+	return key
+}
+
+// observe returns true for the observed table. The table matching the schemas configuration
+// is added to the metadata without columns when it is met for the first time, the columns are loaded on demand.
+func (r *Listener) observe(schema string, table string) bool {
+	if r.dbMetadata.Contains(schema, table) {
+		return true
+	}
+	if r.selector == nil {
+		return false
+	}
+	key := schema + "." + table
+	if _, ok := r.ignored[key]; ok {
+		return false
+	}
+	if !r.selector.Matches(schema, table) {
+		r.ignored[key] = struct{}{}
+		return false
+	}
+	logger.Info().Str("schema", schema).Str("table", table).Msg("table matches the schemas configuration, observe it")
+	r.dbMetadata.Add(schema, table, nil)
+	return true
+}
+
+// tableFields returns column names written to table map event by server with binlog_row_metadata=FULL option,
+// otherwise column names valid at the binlog position are taken from the schema history
+// or column names loaded from information_schema are used.
+func (r *Listener) tableFields(tableMap *replication.TableMapEvent, bs *binlogState, header *replication.EventHeader) []string {
+	if fields := tableMap.ColumnNameString(); len(fields) != 0 {
+		return fields
+	}
+	schema, table := string(tableMap.Schema), string(tableMap.Table)
+	if r.history != nil {
+		if version, ok := r.history.Effective(schema, table, bs.logName, header.LogPos, bs.gtids.set()); ok {
+			if version.Approximate {
+				logger.Trace().Str("schema", schema).Str("table", table).Msg("use approximate schema version recorded when replication started")
+			}
+			return version.Fields
+		}
+	}
+	logger.Trace().Str("schema", schema).Str("table", table).Msg("table map event doesn't contain column names, use information_schema metadata")
+	return r.dbMetadata.GetFields(schema, table)
+}
+
+// recordBaseline records the current layout of observed tables without history when replication starts,
+// so the layout is used for the rows before the first recorded DDL when the binlog is read again.
+// DDL executed between the start position and the listener start isn't known, so the version is marked as approximate.
+func (r *Listener) recordBaseline(bs *binlogState, header *replication.EventHeader) error {
+	if r.history == nil || bs.commitName == "" {
+		return nil
+	}
+	gtidSet := bs.gtids.String()
+	for schema, tables := range r.dbMetadata {
+		for table, fields := range tables {
+			if r.history.Contains(schema, table) {
+				continue
+			}
+			if err := r.history.Record(database.SchemaVersion{
+				Name:        bs.commitName,
+				Pos:         bs.commitPos,
+				GTIDSet:     gtidSet,
+				Approximate: true,
+				Schema:      schema,
+				Table:       table,
+				Fields:      fields,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applySchemaChange updates metadata of the observed table changed by DDL at the binlog position.
+// information_schema contains the current layout only, so the version recorded to the schema history
+// is used when the DDL is read again.
+func (r *Listener) applySchemaChange(schema string, table string, dropped bool, bs *binlogState, header *replication.EventHeader) error {
+	if r.history != nil {
+		if version, ok := r.history.Version(schema, table, bs.logName, header.LogPos, bs.gtid); ok {
+			r.dbMetadata.SetFields(schema, table, version.Fields)
+			return nil
+		}
+	}
+	if err := r.refreshTable(schema, table, dropped); err != nil {
+		return err
+	}
+	if r.history == nil {
+		return nil
+	}
+	gtidSet, err := bs.gtids.including(bs.gtid)
+	if err != nil {
+		return err
+	}
+	return r.history.Record(database.SchemaVersion{
+		Name:    bs.logName,
+		Pos:     header.LogPos,
+		GTID:    bs.gtid,
+		GTIDSet: gtidSet,
+		Schema:  schema,
+		Table:   table,
+		Fields:  r.dbMetadata.GetFields(schema, table),
+	})
+}
+
+// refreshTable reloads column names of the observed table from information_schema after DDL.
+func (r *Listener) refreshTable(schema string, table string, dropped bool) error {
+	if dropped {
+		logger.Info().Str("schema", schema).Str("table", table).Msg("table is dropped, clean its metadata")
+		r.dbMetadata.SetFields(schema, table, nil)
+		return nil
+	}
+	fields, err := r.loadFields(schema, table)
+	if errors.Is(err, database.ErrNoColumns) {
+		logger.Warn().Err(err).Str("schema", schema).Str("table", table).Msg("table doesn't exist anymore, clean its metadata")
+		r.dbMetadata.SetFields(schema, table, nil)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("refreshing %s.%s table metadata failure: %w", schema, table, err)
+	}
+	r.dbMetadata.SetFields(schema, table, fields)
+	return nil
+}
+
+func (r *Listener) processQueryEvent(event *replication.BinlogEvent, bs *binlogState) error {
 	queryEvent, ok := event.Event.(*replication.QueryEvent)
 	if !ok {
-		return fmt.Errorf("cast event failure")
+		return fmt.Errorf("%w: cast event failure", errInvalidEvent)
 	}
 	schema := string(queryEvent.Schema)
 	query := string(queryEvent.Query)
+	switch strings.ToUpper(strings.TrimSpace(query)) {
+	case beginQuery:
+		bs.inTransaction = true
+		if r.tx != nil {
+			r.tx.begin(transactionID(bs, event.Header))
+		}
+		return nil
+	case commitQuery:
+		bs.commit(event.Header)
+		return r.commitTransaction(event, bs)
+	case rollbackQuery:
+		bs.commit(event.Header)
+		if r.tx != nil && r.tx.active {
+			logger.Debug().Str("transaction-id", r.tx.id).Int("messages", r.tx.count).Msg("transaction is rolled back")
+			r.tx.discard()
+		}
+		return nil
+	}
 	exSchema, exTable, operation, ok := bean.ExtractOperation(query)
 	if !ok {
 		return nil
@@ -257,43 +744,76 @@ func (r *Listener) processQueryEvent(event *replication.BinlogEvent, logName str
 	if schema == "" && exSchema != "" {
 		schema = exSchema
 	}
+	if operation.ChangesSchema() {
+		tableSchema := component.OrDefaultIfEmpty(exSchema, string(queryEvent.Schema))
+		if r.observe(tableSchema, exTable) {
+			if err := r.applySchemaChange(tableSchema, exTable, operation.DropsTable(), bs, event.Header); err != nil {
+				return err
+			}
+		}
+	}
 	bean := bean.NewQuery(schema, exTable, query, operation)
-	metadata := createMetadata(logName, event.Header.LogPos, logSeqNum, logTimestamp)
+	metadata := r.createMetadata(bs, event.Header)
 	return r.putToBatch(bean, metadata)
 }
 
-func (r *Listener) putToBatch(bean bean.Bean, metadata map[string]string) error {
-	if bean.Splittable() {
-		mdSize := metadataSize(r.alias, metadata)
-		size := bean.SizeBytes() + mdSize
-		if size > r.maxSize {
-			parts := bean.Split(r.maxSize - mdSize)
-			for _, part := range parts {
-				data, err := part.Serialize()
-				if err != nil {
-					return fmt.Errorf("event part serialization failure: %w", err)
-				}
-
-				if err := r.batchMessage(data, r.alias, metadata); err != nil {
-					return fmt.Errorf("batching event part failure: %w", err)
-				}
+func (r *Listener) putToBatch(msg bean.Bean, metadata map[string]string) error {
+	mdSize := metadataSize(r.alias, metadata)
+	parts := []bean.Bean{msg}
+	if size := msg.SizeBytes(); msg.Splittable() && size+mdSize > r.maxSize {
+		// space for part properties is reserved, the quantity of parts can't exceed the size
+		parts = msg.Split(r.maxSize - mdSize - splitPropsSize(metadata, size))
+	}
+	for i, part := range parts {
+		data, err := part.Serialize()
+		if err != nil {
+			return fmt.Errorf("%w: serialization failure: %w", errInvalidEvent, err)
+		}
+		partMetadata := metadata
+		if len(parts) > 1 {
+			partMetadata = partMetadataOf(metadata, i, len(parts))
+		}
+		// a part with a single row can still exceed the limit
+		if len(data)+metadataSize(r.alias, partMetadata) > r.maxSize {
+			if err := r.putOversized(part, data, partMetadata, i); err != nil {
+				return err
 			}
-			return nil
+			continue
+		}
+		if err := r.batchMessage(data, r.alias, partMetadata); err != nil {
+			return fmt.Errorf("batching event failure: %w", err)
 		}
 	}
+	return nil
+}
 
-	data, err := bean.Serialize()
-	if err != nil {
-		return fmt.Errorf("serialization failure: %w", err)
+// commitTransaction publishes buffered messages of the transaction followed by commit marker.
+func (r *Listener) commitTransaction(event *replication.BinlogEvent, bs *binlogState) error {
+	if r.tx == nil || !r.tx.active {
+		return nil
 	}
-
-	if err := r.batchMessage(data, r.alias, metadata); err != nil {
-		return fmt.Errorf("batching event failure: %w", err)
+	id, count := r.tx.id, r.tx.count
+	if err := r.tx.commit(func(data []byte, metadata map[string]string) error {
+		return r.sendToBatcher(data, r.alias, metadata)
+	}); err != nil {
+		return fmt.Errorf("publishing transaction %s failure: %w", id, err)
 	}
-	return nil
+	if count == 0 {
+		return nil
+	}
+	metadata := r.createMetadata(bs, event.Header)
+	metadata[transactionProp] = id
+	return r.putToBatch(bean.NewCommit(id, count), metadata)
 }
 
 func (r *Listener) batchMessage(data []byte, alias string, metadata map[string]string) error {
+	if r.tx != nil && r.tx.active {
+		return r.tx.add(data, metadata)
+	}
+	return r.sendToBatcher(data, alias, metadata)
+}
+
+func (r *Listener) sendToBatcher(data []byte, alias string, metadata map[string]string) error {
 	if err := r.batcher.Send(data, b.MessageArguments{
 		Metadata:  metadata,
 		Alias:     alias,
@@ -302,6 +822,8 @@ func (r *Listener) batchMessage(data []byte, alias string, metadata map[string]s
 	}); err != nil {
 		return fmt.Errorf("batching failure: %w", err)
 	}
+	r.lastSent = metadata
+	r.sentCount++
 	logger.Trace().Msg("message is sent to batcher")
 	return nil
 }
@@ -314,6 +836,14 @@ func metadataSize(alias string, metadata map[string]string) int {
 	return size
 }
 
+// transactionID returns GTID of the transaction or binlog position of the event started it.
+func transactionID(bs *binlogState, header *replication.EventHeader) string {
+	if bs.gtid != "" {
+		return bs.gtid
+	}
+	return fmt.Sprintf("%s:%d", bs.logName, header.LogPos-header.EventSize)
+}
+
 func logEvent(event *replication.BinlogEvent) {
 	if logger.Debug().Enabled() {
 		buf := new(bytes.Buffer)
@@ -322,11 +852,35 @@ func logEvent(event *replication.BinlogEvent) {
 	}
 }
 
-func createMetadata(logName string, logPos uint32, logSeqNum int64, logTimestamp time.Time) map[string]string {
-	return map[string]string{
-		logNameProp:      logName,
-		logPosProp:       fmt.Sprint(logPos),
-		logSeqNumProp:    fmt.Sprint(logSeqNum),
+// createMetadata builds message properties for event.
+// Event header timestamp is used when binlog doesn't contain commit time (GTID events are absent or written by MySQL 5.7).
+// Events of the buffered transaction get its id, so the property is counted when the message size is checked.
+func (r *Listener) createMetadata(bs *binlogState, header *replication.EventHeader) map[string]string {
+	logTimestamp := bs.logTimestamp
+	if logTimestamp.IsZero() {
+		logTimestamp = time.Unix(int64(header.Timestamp), 0)
+	}
+	metadata := map[string]string{
+		logNameProp:      bs.logName,
+		logPosProp:       fmt.Sprint(header.LogPos),
+		logSeqNumProp:    fmt.Sprint(bs.logSeqNum),
 		logTimestampProp: fmt.Sprint(logTimestamp.UnixNano()),
 	}
+	if bs.gtid != "" {
+		metadata[gtidProp] = bs.gtid
+	}
+	if !bs.originalTimestamp.IsZero() {
+		metadata[originalTimeProp] = fmt.Sprint(bs.originalTimestamp.UnixNano())
+	}
+	if bs.commitName != "" {
+		metadata[commitNameProp] = bs.commitName
+		metadata[commitPosProp] = fmt.Sprint(bs.commitPos)
+	}
+	if gtidSet := bs.gtids.String(); gtidSet != "" {
+		metadata[gtidSetProp] = gtidSet
+	}
+	if r.tx != nil && r.tx.active {
+		metadata[transactionProp] = r.tx.id
+	}
+	return metadata
 }
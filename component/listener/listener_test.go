@@ -0,0 +1,410 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/rules"
+)
+
+func TestTableFields(t *testing.T) {
+	r := testListener(nil, false)
+	tests := []struct {
+		name     string
+		tableMap *replication.TableMapEvent
+		fields   []string
+	}{
+		{
+			name: "full row metadata",
+			tableMap: &replication.TableMapEvent{
+				Schema:     []byte(testSchema),
+				Table:      []byte(testTable),
+				ColumnName: [][]byte{[]byte("id"), []byte("name")},
+			},
+			fields: []string{"id", "name"},
+		},
+		{
+			name:     "minimal row metadata",
+			tableMap: &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)},
+			fields:   []string{"id"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if fields := r.tableFields(tc.tableMap, &binlogState{}, &replication.EventHeader{}); !slices.Equal(fields, tc.fields) {
+				t.Fatalf("%v fields expected, got %v", tc.fields, fields)
+			}
+		})
+	}
+}
+
+func TestSkipNotObservedTable(t *testing.T) {
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, false)
+	e := &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.WRITE_ROWS_EVENTv2, LogPos: 100},
+		Event: &replication.RowsEvent{
+			Table: &replication.TableMapEvent{
+				Schema:     []byte(testSchema),
+				Table:      []byte("other_table"),
+				ColumnName: [][]byte{[]byte("id")},
+			},
+			Rows: [][]any{{int64(1)}},
+		},
+	}
+	newInsert := func(schema string, table string, fields []string, rows [][]any) bean.Bean {
+		return bean.NewInsert(schema, table, fields, rows)
+	}
+	if err := r.processRowsEvent(e, &binlogState{}, rules.Insert, newInsert); err != nil {
+		t.Fatal(err)
+	}
+	if len(batcher.messages) != 0 {
+		t.Fatalf("not observed table is expected to be skipped, got %v", batcher.messages)
+	}
+}
+
+func TestRefreshTableOnDDL(t *testing.T) {
+	r := testListener(&fakeBatcher{}, false)
+	r.loadFields = func(schema string, table string) ([]string, error) {
+		return []string{"id", "name"}, nil
+	}
+	query := func(query string) *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.QUERY_EVENT, LogPos: 100},
+			Event:  &replication.QueryEvent{Schema: []byte(testSchema), Query: []byte(query)},
+		}
+	}
+
+	if err := r.processQueryEvent(query("ALTER TABLE test_table ADD COLUMN name TEXT"), &binlogState{}); err != nil {
+		t.Fatal(err)
+	}
+	if fields := r.dbMetadata.GetFields(testSchema, testTable); !slices.Equal(fields, []string{"id", "name"}) {
+		t.Fatalf("refreshed fields expected, got %v", fields)
+	}
+	if err := r.processQueryEvent(query("DROP TABLE test_table"), &binlogState{}); err != nil {
+		t.Fatal(err)
+	}
+	if !r.dbMetadata.Contains(testSchema, testTable) || r.dbMetadata.GetFields(testSchema, testTable) != nil {
+		t.Fatalf("dropped table is expected to be observed without fields, got %v", r.dbMetadata)
+	}
+}
+
+func TestColumnCountMismatch(t *testing.T) {
+	newInsert := func(schema string, table string, fields []string, rows [][]any) bean.Bean {
+		return bean.NewInsert(schema, table, fields, rows)
+	}
+	rowsEvent := func() *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.WRITE_ROWS_EVENTv2, LogPos: 100},
+			Event: &replication.RowsEvent{
+				Table:       &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)},
+				ColumnCount: 2,
+				Rows:        [][]any{{int64(1), "test"}},
+			},
+		}
+	}
+
+	t.Run("recovered", func(t *testing.T) {
+		batcher := &fakeBatcher{}
+		r := testListener(batcher, false)
+		r.loadFields = func(schema string, table string) ([]string, error) {
+			return []string{"id", "name"}, nil
+		}
+		if err := r.processRowsEvent(rowsEvent(), &binlogState{}, rules.Insert, newInsert); err != nil {
+			t.Fatal(err)
+		}
+		if len(batcher.messages) != 1 || !strings.Contains(batcher.messages[0].data, `"name":"test"`) {
+			t.Fatalf("message with refreshed fields expected, got %v", batcher.messages)
+		}
+	})
+	t.Run("failed", func(t *testing.T) {
+		batcher := &fakeBatcher{}
+		r := testListener(batcher, false)
+		err := r.processRowsEvent(rowsEvent(), &binlogState{}, rules.Insert, newInsert)
+		if err == nil || retryable(err) {
+			t.Fatalf("not retryable error expected, got %v", err)
+		}
+		if len(batcher.messages) != 0 {
+			t.Fatalf("no messages expected, got %v", batcher.messages)
+		}
+	})
+}
+
+func TestCommitTimeProperties(t *testing.T) {
+	const headerTimestamp = 1_700_000_000
+	tests := []struct {
+		name     string
+		gtid     *replication.GTIDEvent
+		expected map[string]string
+		absent   []string
+	}{
+		{
+			name: "original commit time",
+			gtid: &replication.GTIDEvent{SID: make([]byte, 16), GNO: 42, SequenceNumber: 7,
+				ImmediateCommitTimestamp: 1_700_000_001_000_001, OriginalCommitTimestamp: 1_700_000_000_500_000},
+			expected: map[string]string{
+				gtidProp:         "00000000-0000-0000-0000-000000000000:42",
+				logSeqNumProp:    "7",
+				logTimestampProp: "1700000001000001000",
+				originalTimeProp: "1700000000500000000",
+			},
+		},
+		{
+			name: "commit time isn't written",
+			gtid: &replication.GTIDEvent{SID: make([]byte, 16), GNO: 43, SequenceNumber: 8},
+			expected: map[string]string{
+				gtidProp:         "00000000-0000-0000-0000-000000000000:43",
+				logSeqNumProp:    "8",
+				logTimestampProp: "1700000000000000000",
+			},
+			absent: []string{originalTimeProp},
+		},
+		{
+			name: "without GTID event",
+			expected: map[string]string{
+				logSeqNumProp:    "0",
+				logTimestampProp: "1700000000000000000",
+			},
+			absent: []string{gtidProp, originalTimeProp},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			binlog := newTestBinlog().file("binlog.000001")
+			if tc.gtid != nil {
+				binlog.add(replication.GTID_EVENT, tc.gtid)
+			}
+			binlog.query("BEGIN").insert().xid()
+			for _, e := range binlog.files[0].events {
+				e.Header.Timestamp = headerTimestamp
+			}
+			messages := run(t, binlog, false, state{})
+			if len(messages) != 1 {
+				t.Fatalf("1 message expected, got %d", len(messages))
+			}
+			metadata := messages[0].metadata
+			for key, value := range tc.expected {
+				if metadata[key] != value {
+					t.Fatalf("'%s' %s property expected, got %v", value, key, metadata)
+				}
+			}
+			for _, key := range tc.absent {
+				if _, ok := metadata[key]; ok {
+					t.Fatalf("%s property isn't expected, got %v", key, metadata)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayWithSchemaHistory(t *testing.T) {
+	history, err := database.OpenSchemaHistory(filepath.Join(t.TempDir(), "history.jsonl"), mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer history.Close()
+
+	binlog := newTestBinlog().
+		file("binlog.000001").
+		query("BEGIN").insert().xid().
+		query("ALTER TABLE test_table ADD COLUMN name TEXT").
+		query("BEGIN").add(replication.WRITE_ROWS_EVENTv2, &replication.RowsEvent{
+		Table:       &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)},
+		ColumnCount: 2,
+		Rows:        [][]any{{int64(2), "test"}},
+	}).xid()
+
+	process := func(initial []string, current []string) []sentMessage {
+		batcher := &fakeBatcher{}
+		r := testListener(batcher, false)
+		r.history = history
+		r.dbMetadata.SetFields(testSchema, testTable, initial)
+		r.loadFields = func(schema string, table string) ([]string, error) {
+			return current, nil
+		}
+		if err := r.process(context.Background(), binlog.source(state{}), state{}); !errors.Is(err, errEndOfBinlog) {
+			t.Fatalf("end of binlog expected, got: %v", err)
+		}
+		return batcher.messages
+	}
+
+	live := process([]string{"id"}, []string{"id", "name"})
+	// the table is changed again after the first read, information_schema has the current layout only
+	current := []string{"id", "name", "created"}
+	replayed := process(current, current)
+	if len(live) != 3 || len(replayed) != len(live) {
+		t.Fatalf("3 messages expected, got %d and %d", len(live), len(replayed))
+	}
+	for i := range live {
+		if live[i].data != replayed[i].data {
+			t.Fatalf("message %d expected %s, got %s", i, live[i].data, replayed[i].data)
+		}
+	}
+	if !strings.Contains(replayed[2].data, `"name":"test"`) {
+		t.Fatalf("message decoded by recorded schema version expected, got %s", replayed[2].data)
+	}
+}
+
+func TestObserveCreatedTable(t *testing.T) {
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, false)
+	selector, err := database.NewTableSelector(conf.SchemasConf{testSchema: {testTable, "orders_*"}}, conf.SchemasConf{testSchema: {"orders_tmp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.selector = selector
+	r.ignored = make(map[string]struct{})
+	r.loadFields = func(schema string, table string) ([]string, error) {
+		return []string{"id", "amount"}, nil
+	}
+	newInsert := func(schema string, table string, fields []string, rows [][]any) bean.Bean {
+		return bean.NewInsert(schema, table, fields, rows)
+	}
+	insert := func(table string) *replication.BinlogEvent {
+		return &replication.BinlogEvent{
+			Header: &replication.EventHeader{EventType: replication.WRITE_ROWS_EVENTv2, LogPos: 300},
+			Event: &replication.RowsEvent{
+				Table:       &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(table)},
+				ColumnCount: 2,
+				Rows:        [][]any{{int64(1), int64(100)}},
+			},
+		}
+	}
+
+	create := &replication.BinlogEvent{
+		Header: &replication.EventHeader{EventType: replication.QUERY_EVENT, LogPos: 200},
+		Event:  &replication.QueryEvent{Schema: []byte(testSchema), Query: []byte("CREATE TABLE orders_2025 (id INT, amount INT)")},
+	}
+	if err := r.processQueryEvent(create, &binlogState{}); err != nil {
+		t.Fatal(err)
+	}
+	if fields := r.dbMetadata.GetFields(testSchema, "orders_2025"); !slices.Equal(fields, []string{"id", "amount"}) {
+		t.Fatalf("created table is expected to be observed, got %v", fields)
+	}
+	// the table is created while the listener doesn't run
+	for _, table := range []string{"orders_2025", "orders_2026", "orders_tmp", "customers"} {
+		if err := r.processRowsEvent(insert(table), &binlogState{}, rules.Insert, newInsert); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(batcher.messages) != 3 {
+		t.Fatalf("create query and inserts into 2 tables expected, got %v", batcher.messages)
+	}
+	if r.dbMetadata.Contains(testSchema, "orders_tmp") || r.dbMetadata.Contains(testSchema, "customers") {
+		t.Fatalf("excluded and not matched tables aren't expected to be observed, got %v", r.dbMetadata)
+	}
+}
+
+func TestFilterRows(t *testing.T) {
+	binlog := newTestBinlog().
+		file("binlog.000001").
+		query("BEGIN").insert().xid().
+		query("BEGIN").insert().xid().
+		query("BEGIN").insert().xid()
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, false)
+	r.alias = "filter-test"
+	var err error
+	if r.rules, err = rules.New([]conf.TableRules{
+		{Schema: testSchema, Table: testTable, Filters: []conf.RowFilter{{Operations: []string{"INSERT"}, Expression: "id != 2"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// the counter is global, so it isn't reset when the test is repeated
+	filteredBefore := testutil.ToFloat64(filteredRowsTotal.WithLabelValues("filter-test", testSchema, testTable, "INSERT"))
+	if err := r.process(context.Background(), binlog.source(state{}), state{}); !errors.Is(err, errEndOfBinlog) {
+		t.Fatalf("end of binlog expected, got: %v", err)
+	}
+
+	if len(batcher.messages) != 2 {
+		t.Fatalf("2 inserts expected, got %v", batcher.messages)
+	}
+	// the commit boundary is advanced by the transaction with the filtered row
+	if pos := batcher.messages[1].metadata[commitPosProp]; pos != "604" {
+		t.Fatalf("commit position of the second transaction expected, got %s", pos)
+	}
+	filtered := testutil.ToFloat64(filteredRowsTotal.WithLabelValues("filter-test", testSchema, testTable, "INSERT")) - filteredBefore
+	if filtered != 1 {
+		t.Fatalf("1 filtered row expected, got %v", filtered)
+	}
+}
+
+func TestKeyColumns(t *testing.T) {
+	fields := []string{"id", "region", "amount"}
+	full := &replication.TableMapEvent{
+		Schema:     []byte(testSchema),
+		Table:      []byte(testTable),
+		ColumnName: [][]byte{[]byte("id"), []byte("region"), []byte("amount")},
+		PrimaryKey: []uint64{1, 0},
+	}
+	// binlog_row_metadata=MINIMAL: neither column names nor primary key are written
+	minimal := &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)}
+	tests := []struct {
+		name       string
+		tableMap   *replication.TableMapEvent
+		configured []string
+		expected   []string
+	}{
+		{name: "primary key", tableMap: full, expected: []string{"region", "id"}},
+		{name: "table without primary key", tableMap: &replication.TableMapEvent{ColumnName: full.ColumnName}, expected: []string{}},
+		{name: "unknown primary key", tableMap: minimal, expected: nil},
+		{name: "configured key", tableMap: minimal, configured: []string{"id"}, expected: []string{"id"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key := testListener(nil, false).keyColumns(tc.tableMap, fields, tc.configured)
+			if !reflect.DeepEqual(key, tc.expected) {
+				t.Fatalf("%#v key expected, got %#v", tc.expected, key)
+			}
+		})
+	}
+}
+
+func TestSyncerConfigTLS(t *testing.T) {
+	r := testListener(nil, false)
+	r.conf = conf.Connection{Host: "db.example", Port: 3306, Username: "user", Password: "password"}
+	if cfg := r.syncerConfig(); cfg.TLSConfig != nil {
+		t.Fatalf("TLS isn't expected, got %v", cfg.TLSConfig)
+	}
+
+	tlsConfig, err := database.NewTLSConfig(&conf.TLS{}, r.conf.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.tlsConfig = tlsConfig
+	cfg := r.syncerConfig()
+	if cfg.TLSConfig != tlsConfig || cfg.TLSConfig.ServerName != "db.example" {
+		t.Fatalf("TLS config expected, got %v", cfg.TLSConfig)
+	}
+	if cfg.Host != "db.example" || cfg.Port != 3306 || cfg.User != "user" {
+		t.Fatalf("connection settings expected, got %+v", cfg)
+	}
+}
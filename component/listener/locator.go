@@ -0,0 +1,189 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	locateTimeout = 10 * time.Minute
+)
+
+// binlogReader provides binlog files for locating start position by timestamp.
+type binlogReader interface {
+	// files returns binlog files in the creation order
+	files(ctx context.Context) ([]database.BinlogFile, error)
+	// open returns events of the binlog file from the beginning, the events of the next files can follow them
+	open(ctx context.Context, name string) (eventSource, func(), error)
+}
+
+// serverBinlogReader reads binlog files from the source server by replication protocol.
+type serverBinlogReader struct {
+	r *Listener
+}
+
+func (s serverBinlogReader) files(_ context.Context) ([]database.BinlogFile, error) {
+	return database.LoadBinlogFiles(s.r.conf, s.r.tlsConfig)
+}
+
+func (s serverBinlogReader) open(_ context.Context, name string) (eventSource, func(), error) {
+	syncer := s.r.newSyncer()
+	streamer, err := syncer.StartSync(mysql.Position{Name: name, Pos: binlogFirstEventPos})
+	if err != nil {
+		syncer.Close()
+		return nil, nil, fmt.Errorf("starting sync '%s' binlog failure: %w", name, err)
+	}
+	return streamer, syncer.Close, nil
+}
+
+func (r *Listener) locateTimestamp(ctx context.Context, timestamp time.Time) (state, error) {
+	ctx, cancel := context.WithTimeout(ctx, locateTimeout)
+	defer cancel()
+	return locateTimestamp(ctx, serverBinlogReader{r: r}, timestamp)
+}
+
+// locateTimestamp returns the boundary before the first transaction committed not earlier than the timestamp.
+// The last binlog file created before the timestamp is chosen by the first event time, then the file is scanned
+// until commit time of a transaction reaches the timestamp. The end of the file is returned when all transactions of the file
+// are committed earlier.
+func locateTimestamp(ctx context.Context, reader binlogReader, timestamp time.Time) (state, error) {
+	files, err := reader.files(ctx)
+	if err != nil {
+		return state{}, err
+	}
+	if len(files) == 0 {
+		return state{}, errors.New("no one binlog file is found")
+	}
+	index := 0
+	for i := len(files) - 1; i > 0; i-- {
+		created, err := fileTimestamp(ctx, reader, files[i].Name)
+		if err != nil {
+			return state{}, err
+		}
+		// all transactions of the previous files are committed not later than the file creation
+		if created.Before(timestamp) {
+			index = i
+			break
+		}
+	}
+	file := files[index]
+	logger.Info().Str("file", file.Name).Time("timestamp", timestamp).Msg("binlog file is chosen by timestamp, scan it")
+	st, err := scanFile(ctx, reader, file, timestamp)
+	if err != nil {
+		return state{}, err
+	}
+	logger.Info().Stringer("state", st).Time("timestamp", timestamp).Msg("binlog position is located by timestamp")
+	return st, nil
+}
+
+// fileTimestamp returns time of the first event in the binlog file.
+func fileTimestamp(ctx context.Context, reader binlogReader, name string) (time.Time, error) {
+	source, closeSource, err := reader.open(ctx, name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer closeSource()
+	for {
+		e, err := source.GetEvent(ctx)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("reading the first event of '%s' binlog failure: %w", name, err)
+		}
+		// server sends fake rotate event with zero timestamp before events of the file
+		if e.Header.Timestamp != 0 {
+			return time.Unix(int64(e.Header.Timestamp), 0), nil
+		}
+	}
+}
+
+// scanFile tracks transaction boundaries in the same way as the listener and stops on the first transaction
+// committed not earlier than the timestamp. Commit time is taken from GTID event when it is written,
+// otherwise time of the event completing the transaction is used.
+func scanFile(ctx context.Context, reader binlogReader, file database.BinlogFile, timestamp time.Time) (state, error) {
+	source, closeSource, err := reader.open(ctx, file.Name)
+	if err != nil {
+		return state{}, err
+	}
+	defer closeSource()
+
+	bs := &binlogState{logName: file.Name, commitName: file.Name, commitPos: binlogFirstEventPos}
+	var commitTime time.Time
+	// complete checks commit time of the transaction finished by the event
+	complete := func(header *replication.EventHeader) bool {
+		if commitTime.IsZero() {
+			commitTime = time.Unix(int64(header.Timestamp), 0)
+		}
+		if !commitTime.Before(timestamp) {
+			return true
+		}
+		bs.commit(header)
+		commitTime = time.Time{}
+		return false
+	}
+	for {
+		e, err := source.GetEvent(ctx)
+		if err != nil {
+			return state{}, fmt.Errorf("scanning '%s' binlog failure: %w", file.Name, err)
+		}
+		header := e.Header
+		switch header.EventType {
+		case replication.ROTATE_EVENT:
+			// the fake rotate event has zero position
+			if header.LogPos != 0 {
+				return stateAt(bs), nil
+			}
+		case replication.GTID_EVENT, replication.ANONYMOUS_GTID_EVENT:
+			commitTime = e.Event.(*replication.GTIDEvent).ImmediateCommitTime()
+		case replication.MARIADB_GTID_EVENT:
+			if !e.Event.(*replication.MariadbGTIDEvent).IsStandalone() {
+				bs.inTransaction = true
+			}
+		case replication.QUERY_EVENT, replication.MARIADB_QUERY_COMPRESSED_EVENT:
+			query := strings.ToUpper(strings.TrimSpace(string(e.Event.(*replication.QueryEvent).Query)))
+			if query == beginQuery {
+				bs.inTransaction = true
+				break
+			}
+			// statements inside transaction don't complete it
+			if bs.inTransaction && query != commitQuery && query != rollbackQuery {
+				break
+			}
+			if complete(header) {
+				return stateAt(bs), nil
+			}
+		case replication.XID_EVENT:
+			if complete(header) {
+				return stateAt(bs), nil
+			}
+		}
+		if file.Size > 0 && uint64(header.LogPos) >= file.Size {
+			return stateAt(bs), nil
+		}
+	}
+}
+
+func stateAt(bs *binlogState) state {
+	return state{position: mysql.Position{Name: bs.commitName, Pos: bs.commitPos}}
+}
@@ -0,0 +1,114 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+// testBinlogReader provides test binlog for locating by timestamp, size of the file is the end of its last event.
+type testBinlogReader struct {
+	*testBinlog
+}
+
+func (l testBinlogReader) files(_ context.Context) ([]database.BinlogFile, error) {
+	files := make([]database.BinlogFile, 0, len(l.testBinlog.files))
+	for _, f := range l.testBinlog.files {
+		var size uint64
+		if len(f.events) != 0 {
+			size = uint64(f.events[len(f.events)-1].Header.LogPos)
+		}
+		files = append(files, database.BinlogFile{Name: f.name, Size: size})
+	}
+	return files, nil
+}
+
+func (l testBinlogReader) open(_ context.Context, name string) (eventSource, func(), error) {
+	return l.source(state{position: mysql.Position{Name: name, Pos: binlogFirstEventPos}}), func() {}, nil
+}
+
+func TestLocateTimestamp(t *testing.T) {
+	t0 := time.Date(2025, 1, 23, 14, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return t0.Add(time.Duration(minutes) * time.Minute)
+	}
+	binlog := newTestBinlog()
+	// format description event starts the file at its creation time
+	file := func(name string, created time.Time) {
+		binlog.at(created).file(name).add(replication.FORMAT_DESCRIPTION_EVENT, &replication.FormatDescriptionEvent{})
+	}
+	var gno int64
+	// transaction adds GTID, BEGIN and XID events, the events have start time of the transaction like server writes
+	transaction := func(start time.Time, commit time.Time) {
+		gno++
+		binlog.at(start).gtidCommitted(gno, commit).query("BEGIN").xid()
+	}
+
+	file("binlog.000001", at(0))
+	transaction(at(5), at(10))
+	tx20 := binlog.pos
+	transaction(at(15), at(20))
+	// the end of the file is the rotate event
+	firstEnd := binlog.pos
+	file("binlog.000002", at(30))
+	transaction(at(35), at(40))
+	tx50 := binlog.pos
+	transaction(at(45), at(50))
+	ddl := binlog.pos
+	// DDL is committed at event time without commit time in GTID event
+	binlog.at(at(60)).gtid(gno + 1).query("CREATE TABLE test_table (id INT)")
+	tx70 := binlog.pos
+	// transaction without commit time in GTID event is committed at XID event time
+	binlog.at(at(65)).gtid(gno + 2).query("BEGIN").at(at(70)).xid()
+	end := binlog.pos
+
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		position  mysql.Position
+	}{
+		{name: "before all files", timestamp: at(-100), position: mysql.Position{Name: "binlog.000001", Pos: binlogFirstEventPos}},
+		{name: "before the first transaction", timestamp: at(1), position: mysql.Position{Name: "binlog.000001", Pos: binlogFirstEventPos}},
+		{name: "exact commit time", timestamp: at(10), position: mysql.Position{Name: "binlog.000001", Pos: binlogFirstEventPos}},
+		{name: "commit time is used instead of start time", timestamp: at(17), position: mysql.Position{Name: "binlog.000001", Pos: tx20}},
+		{name: "between files", timestamp: at(25), position: mysql.Position{Name: "binlog.000001", Pos: firstEnd}},
+		{name: "the second file creation time", timestamp: at(30), position: mysql.Position{Name: "binlog.000001", Pos: firstEnd}},
+		{name: "after the second file creation", timestamp: at(31), position: mysql.Position{Name: "binlog.000002", Pos: binlogFirstEventPos}},
+		{name: "middle of the second file", timestamp: at(45), position: mysql.Position{Name: "binlog.000002", Pos: tx50}},
+		{name: "ddl", timestamp: at(55), position: mysql.Position{Name: "binlog.000002", Pos: ddl}},
+		{name: "xid time", timestamp: at(67), position: mysql.Position{Name: "binlog.000002", Pos: tx70}},
+		{name: "after all transactions", timestamp: at(100), position: mysql.Position{Name: "binlog.000002", Pos: end}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st, err := locateTimestamp(context.Background(), testBinlogReader{binlog}, tc.timestamp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.position != tc.position {
+				t.Fatalf("%s expected, got %s", tc.position, st.position)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+const testServerID = 1
+
+// parseEvent decodes event body written by MariaDB server with testServerID.
+func parseEvent(t *testing.T, eventType replication.EventType, body []byte) replication.Event {
+	t.Helper()
+	header := make([]byte, replication.EventHeaderSize)
+	header[4] = byte(eventType)
+	binary.LittleEndian.PutUint32(header[5:], testServerID)
+	binary.LittleEndian.PutUint32(header[9:], uint32(len(header)+len(body)))
+	parser := replication.NewBinlogParser()
+	parser.SetFlavor(mysql.MariaDBFlavor)
+	event, err := parser.Parse(append(header, body...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event.Event
+}
+
+func mariadbGTIDList(t *testing.T, gtids ...mysql.MariadbGTID) replication.Event {
+	body := binary.LittleEndian.AppendUint32(nil, uint32(len(gtids)))
+	for _, gtid := range gtids {
+		body = binary.LittleEndian.AppendUint32(body, gtid.DomainID)
+		body = binary.LittleEndian.AppendUint32(body, gtid.ServerID)
+		body = binary.LittleEndian.AppendUint64(body, gtid.SequenceNumber)
+	}
+	return parseEvent(t, replication.MARIADB_GTID_LIST_EVENT, body)
+}
+
+func mariadbGTID(t *testing.T, domain uint32, seq uint64, flags byte) replication.Event {
+	body := binary.LittleEndian.AppendUint64(nil, seq)
+	body = binary.LittleEndian.AppendUint32(body, domain)
+	body = append(body, flags, 0, 0, 0, 0, 0, 0)
+	return parseEvent(t, replication.MARIADB_GTID_EVENT, body)
+}
+
+// compressedQuery returns query event compressed by server with log_bin_compress option.
+func compressedQuery(t *testing.T, query string) replication.Event {
+	var compressed bytes.Buffer
+	compressed.WriteByte(0x80 | 4)
+	compressed.Write(binary.BigEndian.AppendUint32(nil, uint32(len(query))))
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(query)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	body := make([]byte, 13)
+	body[8] = byte(len(testSchema))
+	body = append(body, testSchema...)
+	body = append(body, 0)
+	return parseEvent(t, replication.MARIADB_QUERY_COMPRESSED_EVENT, append(body, compressed.Bytes()...))
+}
+
+func TestMariadbBinlog(t *testing.T) {
+	binlog := newTestBinlog().file("mariadb-bin.000001")
+	binlog.add(replication.MARIADB_GTID_LIST_EVENT, mariadbGTIDList(t,
+		mysql.MariadbGTID{DomainID: 0, ServerID: testServerID, SequenceNumber: 5},
+		mysql.MariadbGTID{DomainID: 1, ServerID: 2, SequenceNumber: 3},
+	)).
+		// MariaDB doesn't write BEGIN query, GTID event starts the transaction
+		add(replication.MARIADB_GTID_EVENT, mariadbGTID(t, 0, 6, 0)).
+		add(replication.MARIADB_WRITE_ROWS_COMPRESSED_EVENT_V1, &replication.RowsEvent{
+			Table:       &replication.TableMapEvent{Schema: []byte(testSchema), Table: []byte(testTable)},
+			ColumnCount: 1,
+			Rows:        [][]any{{int64(1)}},
+		}).
+		xid().
+		add(replication.MARIADB_GTID_EVENT, mariadbGTID(t, 0, 7, replication.BINLOG_MARIADB_FL_STANDALONE)).
+		add(replication.MARIADB_QUERY_COMPRESSED_EVENT, compressedQuery(t, "ALTER TABLE test_table ADD COLUMN name TEXT"))
+
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, true)
+	r.conf.GTIDMode = true
+	r.flavor = mysql.MariaDBFlavor
+	if err := r.process(context.Background(), binlog.source(state{}), state{}); !errors.Is(err, errEndOfBinlog) {
+		t.Fatalf("end of binlog expected, got: %v", err)
+	}
+
+	if len(batcher.messages) != 3 {
+		t.Fatalf("insert, commit and query messages expected, got %v", batcher.messages)
+	}
+	insert, commit, query := batcher.messages[0], batcher.messages[1], batcher.messages[2]
+	if insert.metadata[gtidProp] != "0-1-6" || insert.metadata[gtidSetProp] != "0-1-5,1-2-3" || insert.metadata[transactionProp] != "0-1-6" {
+		t.Fatalf("insert with GTID properties expected, got %v", insert.metadata)
+	}
+	if commit.metadata[transactionProp] != "0-1-6" {
+		t.Fatalf("commit of the transaction expected, got %v", commit.metadata)
+	}
+	if !strings.Contains(query.data, "ALTER TABLE test_table ADD COLUMN name TEXT") {
+		t.Fatalf("decompressed query expected, got %s", query.data)
+	}
+	if query.metadata[gtidProp] != "0-1-7" || query.metadata[gtidSetProp] != "0-1-6,1-2-3" {
+		t.Fatalf("standalone query with GTID properties expected, got %v", query.metadata)
+	}
+	if _, ok := query.metadata[transactionProp]; ok {
+		t.Fatalf("standalone query isn't expected to be a part of transaction, got %v", query.metadata)
+	}
+
+	st := r.stateFromProperties(nil, query.metadata)
+	if st.gtidSet == nil || st.gtidSet.String() != "0-1-6,1-2-3" {
+		t.Fatalf("state with MariaDB GTID set expected, got %s", st)
+	}
+}
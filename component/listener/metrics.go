@@ -0,0 +1,61 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	sessionAliasLabel = "session_alias"
+	schemaLabel       = "schema"
+	tableLabel        = "table"
+	operationLabel    = "operation"
+	policyLabel       = "policy"
+)
+
+var (
+	reconnectTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "th2_listener_mysql_binlog_reconnect_total",
+			Help: "Quantity of reconnects to mysql server after replication failures",
+		},
+		[]string{sessionAliasLabel},
+	)
+	filteredRowsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "th2_listener_mysql_binlog_filtered_rows_total",
+			Help: "Quantity of rows skipped by row filters",
+		},
+		[]string{sessionAliasLabel, schemaLabel, tableLabel, operationLabel},
+	)
+	noiseUpdatesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "th2_listener_mysql_binlog_noise_updates_total",
+			Help: "Quantity of updates skipped because only noise columns are changed",
+		},
+		[]string{sessionAliasLabel, schemaLabel, tableLabel},
+	)
+	oversizedRowsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "th2_listener_mysql_binlog_oversized_rows_total",
+			Help: "Quantity of messages exceeding the size limit with a single row",
+		},
+		[]string{sessionAliasLabel, policyLabel},
+	)
+)
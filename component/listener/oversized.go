@@ -0,0 +1,132 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+const (
+	failOversized     = "fail"
+	truncateOversized = "truncate"
+	chunkOversized    = "chunk"
+
+	truncatedProp = "truncated"
+	partProp      = "part"
+	partsProp     = "parts"
+	chunkIDProp   = "chunk-id"
+)
+
+// errOversizedRow stops the listener without reconnect because the same row is read again after reconnect.
+var errOversizedRow = errors.New("row doesn't fit the message size limit")
+
+func newOversizedPolicy(cfg conf.OversizedRows) (string, error) {
+	switch policy := strings.ToLower(cfg.Policy); policy {
+	case "":
+		return failOversized, nil
+	case failOversized, truncateOversized, chunkOversized:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown '%s' oversized rows policy. known values ['%s','%s','%s']",
+			cfg.Policy, failOversized, truncateOversized, chunkOversized)
+	}
+}
+
+// putOversized handles the message bigger than the size limit which can't be split by rows, index is the position
+// of the message among parts of the event.
+func (r *Listener) putOversized(part bean.Bean, data []byte, metadata map[string]string, index int) error {
+	mdSize := metadataSize(r.alias, metadata)
+	oversizedRowsTotal.WithLabelValues(r.alias, r.oversized).Inc()
+	logger.Warn().Str("policy", r.oversized).Int("size", len(data)+mdSize).Int("limit", r.maxSize).
+		Str(logNameProp, metadata[logNameProp]).Str(logPosProp, metadata[logPosProp]).Msg("Message exceeds the size limit")
+	switch r.oversized {
+	case truncateOversized:
+		return r.putTruncated(part, data, metadata, mdSize, index)
+	case chunkOversized:
+		return r.putChunks(data, metadata, mdSize, index)
+	default:
+		err := fmt.Errorf("%w: message size %d bytes exceeds %d bytes limit at %s:%s",
+			errOversizedRow, len(data)+mdSize, r.maxSize, metadata[logNameProp], metadata[logPosProp])
+		r.events.reportError("Oversized row", err, fmt.Sprintf("Position: %s:%s", metadata[logNameProp], metadata[logPosProp]),
+			fmt.Sprintf("Size: %d", len(data)+mdSize), fmt.Sprintf("Limit: %d", r.maxSize),
+			fmt.Sprintf("Set OversizedRows.Policy option to '%s' or '%s' to publish the row", truncateOversized, chunkOversized))
+		return err
+	}
+}
+
+// putTruncated publishes the message with the largest values truncated, names of truncated columns are written to truncated property.
+// The message without values to truncate (transaction commit) is published by chunks.
+func (r *Listener) putTruncated(part bean.Bean, data []byte, metadata map[string]string, mdSize int, index int) error {
+	limit := r.maxSize - mdSize - len(truncatedProp)
+	for {
+		truncated, columns, err := bean.Truncate(part, limit)
+		if errors.Is(err, bean.ErrNotTruncatable) {
+			logger.Warn().Err(err).Str(logNameProp, metadata[logNameProp]).Str(logPosProp, metadata[logPosProp]).
+				Msg("Message can't be truncated, publish it by chunks")
+			return r.putChunks(data, metadata, mdSize, index)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: truncation failure: %w", errOversizedRow, err)
+		}
+		data, err := truncated.Serialize()
+		if err != nil {
+			return fmt.Errorf("%w: truncated message serialization failure: %w", errInvalidEvent, err)
+		}
+		columnsProp := strings.Join(columns, ",")
+		// the property takes space of the message, so values are truncated again when it doesn't fit
+		if len(data)+len(columnsProp) > r.maxSize-mdSize-len(truncatedProp) {
+			limit = r.maxSize - mdSize - len(truncatedProp) - len(columnsProp)
+			continue
+		}
+		truncatedMetadata := maps.Clone(metadata)
+		truncatedMetadata[truncatedProp] = columnsProp
+		if err := r.batchMessage(data, r.alias, truncatedMetadata); err != nil {
+			return fmt.Errorf("batching truncated message failure: %w", err)
+		}
+		return nil
+	}
+}
+
+// putChunks publishes serialized message by chunks, the consumer concatenates bodies of messages with the same chunk-id
+// property in part property order from 1 to parts property value.
+func (r *Listener) putChunks(data []byte, metadata map[string]string, mdSize int, index int) error {
+	chunkID := eventID(metadata) + ":" + strconv.Itoa(index)
+	digits := len(strconv.Itoa(len(data)))
+	chunkSize := r.maxSize - mdSize - len(partProp) - len(partsProp) - 2*digits - len(chunkIDProp) - len(chunkID)
+	if chunkSize <= 0 {
+		return fmt.Errorf("%w: no space for chunk data in %d bytes limit", errOversizedRow, r.maxSize)
+	}
+	parts := (len(data) + chunkSize - 1) / chunkSize
+	for i := range parts {
+		chunk := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+		chunkMetadata := maps.Clone(metadata)
+		chunkMetadata[partProp] = strconv.Itoa(i + 1)
+		chunkMetadata[partsProp] = strconv.Itoa(parts)
+		chunkMetadata[chunkIDProp] = chunkID
+		if err := r.batchMessage(chunk, r.alias, chunkMetadata); err != nil {
+			return fmt.Errorf("batching message chunk failure: %w", err)
+		}
+	}
+	return nil
+}
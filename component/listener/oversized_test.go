@@ -0,0 +1,167 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+const oversizedLimit = 512
+
+// oversizedInsert returns insert of small, big and small rows, the big row doesn't fit the limit alone.
+func oversizedInsert() bean.Insert {
+	return bean.NewInsert(testSchema, testTable, []string{"id", "data"}, [][]any{
+		{int64(1), "small"},
+		{int64(2), strings.Repeat("big value ", 200)},
+		{int64(3), "small"},
+	})
+}
+
+func oversizedListener(policy string) (*Listener, *fakeBatcher) {
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, false)
+	r.maxSize = oversizedLimit
+	r.oversized = policy
+	return r, batcher
+}
+
+func oversizedMetadata() map[string]string {
+	return map[string]string{logNameProp: "binlog.000001", logPosProp: "1234"}
+}
+
+func checkMessageSizes(t *testing.T, r *Listener, messages []sentMessage) {
+	t.Helper()
+	for _, msg := range messages {
+		if size := len(msg.data) + metadataSize(r.alias, msg.metadata); size > r.maxSize {
+			t.Fatalf("message size %d exceeds %d limit: %s %v", size, r.maxSize, msg.data, msg.metadata)
+		}
+	}
+}
+
+func TestOversizedRowFail(t *testing.T) {
+	r, batcher := oversizedListener(failOversized)
+	err := r.putToBatch(oversizedInsert(), oversizedMetadata())
+	if !errors.Is(err, errOversizedRow) {
+		t.Fatalf("oversized row error expected, got %v", err)
+	}
+	if len(batcher.messages) != 1 {
+		t.Fatalf("only the first part expected before failure, got %d messages", len(batcher.messages))
+	}
+}
+
+func TestOversizedRowTruncate(t *testing.T) {
+	r, batcher := oversizedListener(truncateOversized)
+	if err := r.putToBatch(oversizedInsert(), oversizedMetadata()); err != nil {
+		t.Fatal(err)
+	}
+	if len(batcher.messages) != 3 {
+		t.Fatalf("3 messages expected, got %d", len(batcher.messages))
+	}
+	checkMessageSizes(t, r, batcher.messages)
+	truncated := batcher.messages[1]
+	if truncated.metadata[truncatedProp] != "data" {
+		t.Fatalf("truncated data column expected, got %v", truncated.metadata)
+	}
+	var body bean.Insert
+	if err := json.Unmarshal([]byte(truncated.data), &body); err != nil {
+		t.Fatal(err)
+	}
+	value, _ := body.Inserted[0]["data"].(string)
+	if value == "" || !strings.HasPrefix(strings.Repeat("big value ", 200), value) {
+		t.Fatalf("prefix of the value expected, got '%s'", value)
+	}
+	if _, ok := batcher.messages[0].metadata[truncatedProp]; ok {
+		t.Fatalf("the first message isn't truncated, got %v", batcher.messages[0].metadata)
+	}
+}
+
+func TestOversizedQueryTruncate(t *testing.T) {
+	r, batcher := oversizedListener(truncateOversized)
+	query := bean.NewQuery(testSchema, testTable, "ALTER TABLE test_table COMMENT '"+strings.Repeat("c", 1000)+"'", "ALTER_TABLE")
+	if err := r.putToBatch(query, oversizedMetadata()); err != nil {
+		t.Fatal(err)
+	}
+	checkMessageSizes(t, r, batcher.messages)
+	if len(batcher.messages) != 1 || batcher.messages[0].metadata[truncatedProp] != "Query" {
+		t.Fatalf("one message with truncated query expected, got %v", batcher.messages)
+	}
+
+	// commit bean has no values to cut
+	batcher.messages = nil
+	r.maxSize = 80
+	if err := r.putToBatch(bean.NewCommit(strings.Repeat("x", 100), 1), map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	checkMessageSizes(t, r, batcher.messages)
+	if len(batcher.messages) < 2 || batcher.messages[0].metadata[chunkIDProp] == "" {
+		t.Fatalf("commit published by chunks expected, got %v", batcher.messages)
+	}
+}
+
+func TestOversizedRowChunks(t *testing.T) {
+	r, batcher := oversizedListener(chunkOversized)
+	if err := r.putToBatch(oversizedInsert(), oversizedMetadata()); err != nil {
+		t.Fatal(err)
+	}
+	checkMessageSizes(t, r, batcher.messages)
+	chunks := batcher.messages[1 : len(batcher.messages)-1]
+	if len(chunks) < 2 {
+		t.Fatalf("several chunks expected, got %d messages", len(batcher.messages))
+	}
+	var data strings.Builder
+	for i, chunk := range chunks {
+		if chunk.metadata[chunkIDProp] != "binlog.000001:1234:1" {
+			t.Fatalf("chunk id of the second part expected, got %v", chunk.metadata)
+		}
+		if chunk.metadata[partProp] != strconv.Itoa(i+1) {
+			t.Fatalf("part %d expected, got %v", i+1, chunk.metadata)
+		}
+		if chunk.metadata[partsProp] != strconv.Itoa(len(chunks)) {
+			t.Fatalf("%d parts expected, got %v", len(chunks), chunk.metadata)
+		}
+		data.WriteString(chunk.data)
+	}
+	expected, err := bean.Insert{Record: oversizedInsert().Record, Inserted: oversizedInsert().Inserted[1:2]}.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.String() != string(expected) {
+		t.Fatalf("%s expected after reassembly, got %s", expected, data.String())
+	}
+	if _, ok := batcher.messages[0].metadata[chunkIDProp]; ok {
+		t.Fatalf("the first message isn't a chunk, got %v", batcher.messages[0].metadata)
+	}
+}
+
+func TestOversizedRowsPolicy(t *testing.T) {
+	if policy, err := newOversizedPolicy(conf.OversizedRows{}); err != nil || policy != failOversized {
+		t.Fatalf("fail policy expected by default, got %s, %v", policy, err)
+	}
+	if policy, err := newOversizedPolicy(conf.OversizedRows{Policy: "Chunk"}); err != nil || policy != chunkOversized {
+		t.Fatalf("chunk policy expected, got %s, %v", policy, err)
+	}
+	if _, err := newOversizedPolicy(conf.OversizedRows{Policy: "drop"}); err == nil {
+		t.Fatal("error expected")
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+const (
+	defaultMaxAttempts    = 10
+	defaultInitialDelayMs = 1_000
+	defaultMaxDelayMs     = 60_000
+	defaultMultiplier     = 2.0
+)
+
+// errInvalidEvent marks deterministic failure of event processing, the same event fails again after reconnect.
+var errInvalidEvent = errors.New("event can't be processed")
+
+// retryable returns true for connection and replication failures, failures of event processing aren't fixed by reconnect.
+func retryable(err error) bool {
+	return !errors.Is(err, errInvalidEvent) && !errors.Is(err, errOversizedRow)
+}
+
+// backoff calculates exponential delays between reconnect attempts.
+type backoff struct {
+	maxAttempts  int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+}
+
+func newBackoff(cfg conf.Reconnect) backoff {
+	b := backoff{
+		maxAttempts:  cfg.MaxAttempts,
+		initialDelay: time.Duration(cfg.InitialDelayMs) * time.Millisecond,
+		maxDelay:     time.Duration(cfg.MaxDelayMs) * time.Millisecond,
+		multiplier:   cfg.Multiplier,
+	}
+	if b.maxAttempts <= 0 {
+		b.maxAttempts = defaultMaxAttempts
+	}
+	if b.initialDelay <= 0 {
+		b.initialDelay = defaultInitialDelayMs * time.Millisecond
+	}
+	if b.maxDelay <= 0 {
+		b.maxDelay = defaultMaxDelayMs * time.Millisecond
+	}
+	if b.maxDelay < b.initialDelay {
+		b.maxDelay = b.initialDelay
+	}
+	if b.multiplier < 1 {
+		b.multiplier = defaultMultiplier
+	}
+	return b
+}
+
+// delay returns pause before the attempt, attempts are counted from 1.
+func (b backoff) delay(attempt int) time.Duration {
+	delay := float64(b.initialDelay) * math.Pow(b.multiplier, float64(attempt-1))
+	if delay > float64(b.maxDelay) {
+		return b.maxDelay
+	}
+	return time.Duration(delay)
+}
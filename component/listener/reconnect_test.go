@@ -0,0 +1,92 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := newBackoff(conf.Reconnect{InitialDelayMs: 100, MaxDelayMs: 1_000, Multiplier: 3})
+	expected := []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second, time.Second}
+	for i, delay := range expected {
+		if actual := b.delay(i + 1); actual != delay {
+			t.Fatalf("attempt %d: %s delay expected, got %s", i+1, delay, actual)
+		}
+	}
+	// the power overflows float64 range
+	if actual := b.delay(10_000); actual != time.Second {
+		t.Fatalf("delay is expected to be capped, got %s", actual)
+	}
+}
+
+func TestBackoffDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      conf.Reconnect
+		expected backoff
+	}{
+		{
+			name:     "empty",
+			expected: backoff{maxAttempts: 10, initialDelay: time.Second, maxDelay: time.Minute, multiplier: 2},
+		},
+		{
+			name:     "max delay less than initial one",
+			cfg:      conf.Reconnect{MaxAttempts: 3, InitialDelayMs: 5_000, MaxDelayMs: 1_000, Multiplier: 0.5},
+			expected: backoff{maxAttempts: 3, initialDelay: 5 * time.Second, maxDelay: 5 * time.Second, multiplier: 2},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := newBackoff(tc.cfg); actual != tc.expected {
+				t.Fatalf("%+v expected, got %+v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tracker := newGtidTracker(mysql.MySQLFlavor, nil)
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "connection failure", err: fmt.Errorf("getting binlog event failure: %w", io.EOF), retryable: true},
+		{name: "bad connection", err: fmt.Errorf("getting binlog event failure: %w", mysql.ErrBadConn), retryable: true},
+		{name: "server error", err: &mysql.MyError{Code: 1236, Message: "binlog is purged"}, retryable: true},
+		{name: "incorrect GTID set", err: tracker.merge("incorrect"), retryable: false},
+		{name: "conversion failure", err: fmt.Errorf("processing write event failure: %w", fmt.Errorf("%w: converting failure", errInvalidEvent)), retryable: false},
+		{name: "oversized row", err: fmt.Errorf("processing write event failure: %w", errOversizedRow), retryable: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err == nil {
+				t.Fatal("error expected")
+			}
+			if actual := retryable(tc.err); actual != tc.retryable {
+				t.Fatalf("retryable %t expected for %v", tc.retryable, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+const (
+	// Automatic server ids are generated above the range usually used by real servers.
+	minAutoServerID uint32 = 1_000
+	// MySQL limits report host of replica by 60 characters.
+	maxReportHostLen = 60
+)
+
+// autoServerID derives stable server id from th2 session parameters.
+func autoServerID(book string, group string, alias string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.Join([]string{book, group, alias}, "/")))
+	return minAutoServerID + h.Sum32()%(math.MaxUint32-minAutoServerID)
+}
+
+// reportHost returns host name registered by the listener on the source server.
+// It is visible in SHOW REPLICAS query result and identifies the listener's sessions.
+// The long name is cut and ended by hash of the full name, so sessions with the same prefix are distinguished.
+func reportHost(book string, group string, alias string) string {
+	host := "th2:" + strings.Join([]string{book, group, alias}, "/")
+	if len(host) <= maxReportHostLen {
+		return host
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(host))
+	suffix := fmt.Sprintf("~%08x", h.Sum32())
+	return host[:maxReportHostLen-len(suffix)] + suffix
+}
@@ -0,0 +1,55 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoServerID(t *testing.T) {
+	id := autoServerID("book", "group", "alias")
+	if id < minAutoServerID {
+		t.Fatalf("server id above %d expected, got %d", minAutoServerID, id)
+	}
+	if again := autoServerID("book", "group", "alias"); again != id {
+		t.Fatalf("stable server id expected, got %d and %d", id, again)
+	}
+	if other := autoServerID("book", "group", "other"); other == id {
+		t.Fatalf("different server ids expected for different aliases, got %d", other)
+	}
+}
+
+func TestReportHost(t *testing.T) {
+	if host := reportHost("book", "group", "alias"); host != "th2:book/group/alias" {
+		t.Fatalf("full report host expected, got %s", host)
+	}
+	long := strings.Repeat("a", maxReportHostLen)
+	first, second := reportHost("book", "group", long+"1"), reportHost("book", "group", long+"2")
+	if len(first) != maxReportHostLen || len(second) != maxReportHostLen {
+		t.Fatalf("report hosts are expected to be cut to %d characters, got %s and %s", maxReportHostLen, first, second)
+	}
+	if first == second {
+		t.Fatalf("different report hosts expected for aliases with the same prefix, got %s", first)
+	}
+	if !strings.HasPrefix(first, "th2:book/group/aaa") {
+		t.Fatalf("report host is expected to keep the prefix, got %s", first)
+	}
+	if again := reportHost("book", "group", long+"1"); again != first {
+		t.Fatalf("stable report host expected, got %s and %s", first, again)
+	}
+}
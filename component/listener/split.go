@@ -0,0 +1,69 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"maps"
+	"strconv"
+)
+
+const (
+	eventIDProp   = "event-id"
+	partIndexProp = "part-index"
+	partCountProp = "part-count"
+)
+
+// eventID returns identifier of the binlog event which is the same when the event is read again.
+func eventID(metadata map[string]string) string {
+	return metadata[logNameProp] + ":" + metadata[logPosProp]
+}
+
+// splitPropsSize returns the maximum size of properties added to parts of split event, maxParts is the upper bound of parts quantity.
+func splitPropsSize(metadata map[string]string, maxParts int) int {
+	digits := len(strconv.Itoa(maxParts))
+	return len(eventIDProp) + len(eventID(metadata)) + len(partIndexProp) + len(partCountProp) + 2*digits
+}
+
+// partMetadataOf returns copy of the event metadata with properties of the part.
+func partMetadataOf(metadata map[string]string, index int, count int) map[string]string {
+	result := maps.Clone(metadata)
+	result[eventIDProp] = eventID(metadata)
+	result[partIndexProp] = strconv.Itoa(index)
+	result[partCountProp] = strconv.Itoa(count)
+	return result
+}
+
+// incompleteGroup returns true when the message is a part of split event or message chunks and other parts follow it.
+func incompleteGroup(properties map[string]string) bool {
+	// part-index is counted from 0, part is counted from 1
+	return !lastPart(properties[partIndexProp], properties[partCountProp], 1) || !lastPart(properties[partProp], properties[partsProp], 0)
+}
+
+func lastPart(index string, count string, shift int) bool {
+	if index == "" || count == "" {
+		return true
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return false
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return false
+	}
+	return i+shift >= n
+}
@@ -0,0 +1,141 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+)
+
+func TestSplitEventProperties(t *testing.T) {
+	rows := make([][]any, 20)
+	for i := range rows {
+		rows[i] = []any{int64(i), strings.Repeat("value ", 10)}
+	}
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, false)
+	r.maxSize = 512
+	metadata := map[string]string{logNameProp: "binlog.000001", logPosProp: "1234", commitNameProp: "binlog.000001", commitPosProp: "4"}
+	if err := r.putToBatch(bean.NewInsert(testSchema, testTable, []string{"id", "data"}, rows), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if len(batcher.messages) < 2 {
+		t.Fatalf("several parts expected, got %d", len(batcher.messages))
+	}
+	for i, msg := range batcher.messages {
+		if msg.metadata[eventIDProp] != "binlog.000001:1234" ||
+			msg.metadata[partIndexProp] != strconv.Itoa(i) ||
+			msg.metadata[partCountProp] != strconv.Itoa(len(batcher.messages)) {
+			t.Fatalf("properties of part %d expected, got %v", i, msg.metadata)
+		}
+		if size := len(msg.data) + metadataSize(r.alias, msg.metadata); size > r.maxSize {
+			t.Fatalf("message size %d exceeds %d limit", size, r.maxSize)
+		}
+	}
+	if _, ok := metadata[partIndexProp]; ok {
+		t.Fatalf("event metadata is modified: %v", metadata)
+	}
+
+	batcher.messages = nil
+	if err := r.putToBatch(bean.NewInsert(testSchema, testTable, []string{"id", "data"}, rows[:1]), metadata); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := batcher.messages[0].metadata[eventIDProp]; ok {
+		t.Fatalf("part properties aren't expected for not split event, got %v", batcher.messages[0].metadata)
+	}
+}
+
+func TestSplitTransactionEventSize(t *testing.T) {
+	rows := make([][]any, 20)
+	for i := range rows {
+		rows[i] = []any{int64(i), strings.Repeat("value ", 10)}
+	}
+	batcher := &fakeBatcher{}
+	r := testListener(batcher, true)
+	r.maxSize = 512
+	bs := &binlogState{logName: "binlog.000001", commitName: "binlog.000001", commitPos: 4}
+	header := &replication.EventHeader{LogPos: 1234, EventSize: 100}
+	r.tx.begin(transactionID(bs, header))
+	if err := r.putToBatch(bean.NewInsert(testSchema, testTable, []string{"id", "data"}, rows), r.createMetadata(bs, header)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.commitTransaction(&replication.BinlogEvent{Header: header}, bs); err != nil {
+		t.Fatal(err)
+	}
+	if len(batcher.messages) < 3 {
+		t.Fatalf("several parts and commit expected, got %d", len(batcher.messages))
+	}
+	for _, msg := range batcher.messages {
+		if msg.metadata[transactionProp] != "binlog.000001:1134" {
+			t.Fatalf("transaction id expected, got %v", msg.metadata)
+		}
+		if size := len(msg.data) + metadataSize(r.alias, msg.metadata); size > r.maxSize {
+			t.Fatalf("message size %d exceeds %d limit", size, r.maxSize)
+		}
+	}
+}
+
+func TestRestartFromIncompleteSplitEvent(t *testing.T) {
+	position := map[string]string{logNameProp: "binlog.000002", logPosProp: "1234"}
+	tests := []struct {
+		name       string
+		properties map[string]string
+		expected   mysql.Position
+	}{
+		{
+			name:       "not split event",
+			properties: map[string]string{},
+			expected:   mysql.Position{Name: "binlog.000002", Pos: 1234},
+		},
+		{
+			name:       "the last part",
+			properties: map[string]string{partIndexProp: "2", partCountProp: "3"},
+			expected:   mysql.Position{Name: "binlog.000002", Pos: 1234},
+		},
+		{
+			name:       "incomplete split event",
+			properties: map[string]string{partIndexProp: "1", partCountProp: "3"},
+			expected:   mysql.Position{Name: "binlog.000002"},
+		},
+		{
+			name:       "incomplete chunks of the last part",
+			properties: map[string]string{partIndexProp: "2", partCountProp: "3", partProp: "1", partsProp: "2"},
+			expected:   mysql.Position{Name: "binlog.000002"},
+		},
+		{
+			name:       "incomplete split event with commit position",
+			properties: map[string]string{partIndexProp: "0", partCountProp: "3", commitNameProp: "binlog.000001", commitPosProp: "4"},
+			expected:   mysql.Position{Name: "binlog.000001", Pos: 4},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			properties := maps.Clone(position)
+			maps.Copy(properties, tc.properties)
+			st := testListener(nil, false).stateFromProperties(nil, properties)
+			if st.position != tc.expected {
+				t.Fatalf("%v expected, got %s", tc.expected, st)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	startEarliest  = "earliest"
+	startLatest    = "latest"
+	startPosition  = "position"
+	startGTID      = "gtid"
+	startTimestamp = "timestamp"
+
+	// the first event of binlog file follows the 4 bytes magic number
+	binlogFirstEventPos = 4
+)
+
+// startPolicy defines the start point of replication when there are no previous messages of the alias.
+type startPolicy struct {
+	policy    string
+	position  mysql.Position
+	gtidSet   mysql.GTIDSet
+	timestamp time.Time
+}
+
+func newStartPolicy(cfg conf.Start, flavor string) (startPolicy, error) {
+	policy := component.OrDefaultIfEmpty(cfg.Policy, startEarliest)
+	switch policy {
+	case startEarliest, startLatest:
+		return startPolicy{policy: policy}, nil
+	case startPosition:
+		if cfg.Name == "" {
+			return startPolicy{}, errors.New("binlog file name is required for start by position")
+		}
+		pos := cfg.Pos
+		if pos < binlogFirstEventPos {
+			pos = binlogFirstEventPos
+		}
+		return startPolicy{policy: policy, position: mysql.Position{Name: cfg.Name, Pos: pos}}, nil
+	case startGTID:
+		gtidSet, err := mysql.ParseGTIDSet(flavor, cfg.GTIDSet)
+		if err != nil {
+			return startPolicy{}, fmt.Errorf("parsing '%s' GTID set failure: %w", cfg.GTIDSet, err)
+		}
+		return startPolicy{policy: policy, gtidSet: gtidSet}, nil
+	case startTimestamp:
+		timestamp, err := time.Parse(time.RFC3339Nano, cfg.Timestamp)
+		if err != nil {
+			return startPolicy{}, fmt.Errorf("parsing '%s' timestamp failure: %w", cfg.Timestamp, err)
+		}
+		return startPolicy{policy: policy, timestamp: timestamp}, nil
+	default:
+		return startPolicy{}, fmt.Errorf("unknown '%s' start policy. known values ['%s','%s','%s','%s','%s']",
+			policy, startEarliest, startLatest, startPosition, startGTID, startTimestamp)
+	}
+}
+
+// initialState resolves the start point of replication by the start policy.
+func (r *Listener) initialState(ctx context.Context) (state, error) {
+	switch r.start.policy {
+	case startLatest:
+		pos, err := database.LoadBinlogPosition(r.conf, r.tlsConfig)
+		if err != nil {
+			return state{}, fmt.Errorf("loading current binlog position failure: %w", err)
+		}
+		return state{position: mysql.Position{Name: pos.Name, Pos: pos.Pos}}, nil
+	case startPosition:
+		return state{position: r.start.position}, nil
+	case startGTID:
+		return state{gtidSet: r.start.gtidSet.Clone()}, nil
+	case startTimestamp:
+		st, err := r.locateTimestamp(ctx, r.start.timestamp)
+		if err != nil {
+			return state{}, fmt.Errorf("locating binlog position by %s timestamp failure: %w", r.start.timestamp, err)
+		}
+		return st, nil
+	default:
+		return state{}, nil
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+func TestNewStartPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		start  conf.Start
+		policy string
+		state  state
+	}{
+		{
+			name:   "default",
+			start:  conf.Start{},
+			policy: startEarliest,
+		},
+		{
+			name:   "latest",
+			start:  conf.Start{Policy: startLatest},
+			policy: startLatest,
+		},
+		{
+			name:   "position",
+			start:  conf.Start{Policy: startPosition, Name: "binlog.000002", Pos: 1234},
+			policy: startPosition,
+			state:  state{position: mysql.Position{Name: "binlog.000002", Pos: 1234}},
+		},
+		{
+			name:   "position without pos",
+			start:  conf.Start{Policy: startPosition, Name: "binlog.000002"},
+			policy: startPosition,
+			state:  state{position: mysql.Position{Name: "binlog.000002", Pos: 4}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, err := newStartPolicy(tc.start, mysql.MySQLFlavor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start.policy != tc.policy {
+				t.Fatalf("'%s' policy expected, got '%s'", tc.policy, start.policy)
+			}
+			if start.policy == startLatest {
+				return
+			}
+			r := &Listener{start: start}
+			st, err := r.initialState(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.position != tc.state.position || st.gtidSet != nil {
+				t.Fatalf("%s expected, got %s", tc.state, st)
+			}
+		})
+	}
+}
+
+func TestNewStartPolicyGTID(t *testing.T) {
+	start, err := newStartPolicy(conf.Start{Policy: startGTID, GTIDSet: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-23"}, mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := (&Listener{start: start}).initialState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.gtidSet == nil || st.gtidSet.String() != "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-23" {
+		t.Fatalf("GTID set expected, got %s", st)
+	}
+}
+
+func TestNewStartPolicyTimestamp(t *testing.T) {
+	start, err := newStartPolicy(conf.Start{Policy: startTimestamp, Timestamp: "2025-01-23T14:05:00+03:00"}, mysql.MySQLFlavor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2025, 1, 23, 11, 5, 0, 0, time.UTC)
+	if !start.timestamp.Equal(expected) {
+		t.Fatalf("%s timestamp expected, got %s", expected, start.timestamp)
+	}
+}
+
+func TestNewStartPolicyIncorrect(t *testing.T) {
+	tests := []struct {
+		name  string
+		start conf.Start
+	}{
+		{
+			name:  "unknown policy",
+			start: conf.Start{Policy: "newest"},
+		},
+		{
+			name:  "position without name",
+			start: conf.Start{Policy: startPosition, Pos: 4},
+		},
+		{
+			name:  "incorrect gtid set",
+			start: conf.Start{Policy: startGTID, GTIDSet: "incorrect"},
+		},
+		{
+			name:  "incorrect timestamp",
+			start: conf.Start{Policy: startTimestamp, Timestamp: "yesterday"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := newStartPolicy(tc.start, mysql.MySQLFlavor); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// eventSource is a stream of binlog events, it is implemented by replication.BinlogStreamer.
+type eventSource interface {
+	GetEvent(ctx context.Context) (*replication.BinlogEvent, error)
+}
+
+// state is a binlog point to start replication from.
+// GTID set has priority over file and position when it is defined.
+type state struct {
+	position mysql.Position
+	gtidSet  mysql.GTIDSet
+}
+
+func (s state) String() string {
+	if s.gtidSet != nil {
+		return fmt.Sprintf("GTID set: %s", s.gtidSet)
+	}
+	return fmt.Sprintf("position: %s", s.position)
+}
+
+// binlogState accumulates information from service events required for message properties.
+type binlogState struct {
+	logName           string
+	logSeqNum         int64
+	logTimestamp      time.Time
+	originalTimestamp time.Time
+	gtid              string
+	gtids             *gtidTracker
+
+	// commitName and commitPos point to the end of the last committed transaction.
+	inTransaction bool
+	commitName    string
+	commitPos     uint32
+}
+
+// beginTransaction remembers GTID of the transaction started by GTID event.
+func (bs *binlogState) beginTransaction(event mysql.BinlogGTIDEvent) error {
+	next, err := event.GTIDNext()
+	if err != nil {
+		return fmt.Errorf("%w: getting next GTID failure: %w", errInvalidEvent, err)
+	}
+	bs.gtid = next.String()
+	if bs.gtids != nil {
+		return bs.gtids.begin(bs.gtid)
+	}
+	return nil
+}
+
+// commit remembers the end of the event completing transaction as a safe point to resume replication.
+func (bs *binlogState) commit(header *replication.EventHeader) {
+	bs.inTransaction = false
+	bs.commitName = bs.logName
+	bs.commitPos = header.LogPos
+}
+
+// rotate switches binlog file, the beginning of the next file is a safe point to resume replication when it is between transactions.
+func (bs *binlogState) rotate(logName string, pos uint32) {
+	bs.logName = logName
+	if !bs.inTransaction {
+		bs.commitName = logName
+		bs.commitPos = pos
+	}
+}
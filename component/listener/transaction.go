@@ -0,0 +1,146 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	defaultMaxBufferBytes = 64 * 1024 * 1024
+)
+
+type bufferedMessage struct {
+	Data     []byte
+	Metadata map[string]string
+}
+
+// transactionBuffer holds messages of the transaction in progress until it is committed.
+// Messages over the memory limit are spilled to a temporary file.
+type transactionBuffer struct {
+	maxBytes int
+	spillDir string
+
+	id       string
+	active   bool
+	messages []bufferedMessage
+	bytes    int
+	count    int
+
+	spillFile   *os.File
+	spillWriter *bufio.Writer
+	spillEnc    *gob.Encoder
+}
+
+func newTransactionBuffer(maxBytes int, spillDir string) *transactionBuffer {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBufferBytes
+	}
+	return &transactionBuffer{maxBytes: maxBytes, spillDir: spillDir}
+}
+
+// begin starts buffering, the transaction in progress is discarded.
+func (t *transactionBuffer) begin(id string) {
+	if t.active {
+		logger.Warn().Str("transaction-id", t.id).Int("messages", t.count).Msg("transaction isn't completed before the next one, discard it")
+	}
+	t.discard()
+	t.id = id
+	t.active = true
+}
+
+func (t *transactionBuffer) add(data []byte, metadata map[string]string) error {
+	if t.spillEnc == nil && t.bytes+len(data) <= t.maxBytes {
+		t.messages = append(t.messages, bufferedMessage{Data: data, Metadata: metadata})
+		t.bytes += len(data)
+		t.count++
+		return nil
+	}
+	if t.spillEnc == nil {
+		file, err := os.CreateTemp(t.spillDir, "transaction-*.bin")
+		if err != nil {
+			return fmt.Errorf("creating spill file failure: %w", err)
+		}
+		logger.Info().Str("transaction-id", t.id).Str("file", file.Name()).Int("bytes", t.bytes).Msg("transaction buffer is full, spill messages to disk")
+		t.spillFile = file
+		t.spillWriter = bufio.NewWriter(file)
+		t.spillEnc = gob.NewEncoder(t.spillWriter)
+	}
+	if err := t.spillEnc.Encode(bufferedMessage{Data: data, Metadata: metadata}); err != nil {
+		return fmt.Errorf("writing message to spill file failure: %w", err)
+	}
+	t.count++
+	return nil
+}
+
+// commit passes all buffered messages to send function in the original order and resets the buffer.
+func (t *transactionBuffer) commit(send func(data []byte, metadata map[string]string) error) error {
+	defer t.discard()
+	for _, msg := range t.messages {
+		if err := send(msg.Data, msg.Metadata); err != nil {
+			return err
+		}
+	}
+	if t.spillFile == nil {
+		return nil
+	}
+	if err := t.spillWriter.Flush(); err != nil {
+		return fmt.Errorf("flushing spill file failure: %w", err)
+	}
+	if _, err := t.spillFile.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding spill file failure: %w", err)
+	}
+	dec := gob.NewDecoder(bufio.NewReader(t.spillFile))
+	for {
+		var msg bufferedMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading message from spill file failure: %w", err)
+		}
+		if err := send(msg.Data, msg.Metadata); err != nil {
+			return err
+		}
+	}
+}
+
+// discard drops the transaction in progress, it is used for rolled back and incomplete transactions.
+func (t *transactionBuffer) discard() {
+	t.id = ""
+	t.active = false
+	t.messages = nil
+	t.bytes = 0
+	t.count = 0
+	if t.spillFile != nil {
+		name := t.spillFile.Name()
+		if err := t.spillFile.Close(); err != nil {
+			logger.Warn().Err(err).Str("file", name).Msg("closing spill file failure")
+		}
+		if err := os.Remove(name); err != nil {
+			logger.Warn().Err(err).Str("file", name).Msg("removing spill file failure")
+		}
+	}
+	t.spillFile = nil
+	t.spillWriter = nil
+	t.spillEnc = nil
+}
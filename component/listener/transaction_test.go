@@ -0,0 +1,95 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package listener
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestTransactionBufferSpill(t *testing.T) {
+	dir := t.TempDir()
+	tx := newTransactionBuffer(10, dir)
+	tx.begin("trx-1")
+
+	const count = 5
+	for i := 0; i < count; i++ {
+		if err := tx.add([]byte(fmt.Sprintf("data-%d", i)), map[string]string{"index": fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(tx.messages) != 1 {
+		t.Fatalf("messages in memory expected: 1, got: %d", len(tx.messages))
+	}
+	if tx.spillFile == nil {
+		t.Fatal("spill file expected")
+	}
+
+	var received []string
+	err := tx.commit(func(data []byte, metadata map[string]string) error {
+		if string(data) != "data-"+metadata["index"] {
+			return fmt.Errorf("unexpected message %s with %v metadata", data, metadata)
+		}
+		received = append(received, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != count {
+		t.Fatalf("received expected: %d, got: %v", count, received)
+	}
+	for i, data := range received {
+		if data != fmt.Sprintf("data-%d", i) {
+			t.Fatalf("order is broken: %v", received)
+		}
+	}
+	assertEmptyDir(t, dir)
+}
+
+func TestTransactionBufferDiscard(t *testing.T) {
+	dir := t.TempDir()
+	tx := newTransactionBuffer(1, dir)
+	tx.begin("trx-1")
+	if err := tx.add([]byte("data"), map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	tx.begin("trx-2")
+	if tx.id != "trx-2" || tx.count != 0 {
+		t.Fatalf("previous transaction isn't discarded, id: %s, count: %d", tx.id, tx.count)
+	}
+	assertEmptyDir(t, dir)
+
+	tx.discard()
+	err := tx.commit(func(data []byte, metadata map[string]string) error {
+		return fmt.Errorf("discarded message %s is sent", data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("spill files aren't removed: %v", entries)
+	}
+}
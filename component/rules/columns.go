@@ -0,0 +1,167 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	dropAction     = "drop"
+	maskAction     = "mask"
+	hashAction     = "hash"
+	truncateAction = "truncate"
+
+	defaultMaskChar = "*"
+)
+
+type columnRule struct {
+	column      database.NameMatcher
+	action      string
+	replacement string
+	keepFirst   int
+	keepLast    int
+	maskChar    string
+	salt        string
+	length      int
+}
+
+func newColumnRule(cfg conf.ColumnRule) (columnRule, error) {
+	column, err := database.NewNameMatcher(cfg.Column)
+	if err != nil {
+		return columnRule{}, fmt.Errorf("creating column matcher failure: %w", err)
+	}
+	rule := columnRule{column: column, action: cfg.Action}
+	switch cfg.Action {
+	case dropAction:
+	case maskAction:
+		if cfg.KeepFirst < 0 || cfg.KeepLast < 0 {
+			return columnRule{}, fmt.Errorf("'%s' column: negative number of unmasked characters", cfg.Column)
+		}
+		rule.replacement = cfg.Replacement
+		rule.keepFirst = cfg.KeepFirst
+		rule.keepLast = cfg.KeepLast
+		rule.maskChar = cfg.MaskChar
+		if rule.maskChar == "" {
+			rule.maskChar = defaultMaskChar
+		}
+	case hashAction:
+		if cfg.Salt == "" {
+			return columnRule{}, fmt.Errorf("'%s' column: salt is required for hash action", cfg.Column)
+		}
+		rule.salt = cfg.Salt
+	case truncateAction:
+		if cfg.Length <= 0 {
+			return columnRule{}, fmt.Errorf("'%s' column: positive length is required for truncate action", cfg.Column)
+		}
+		rule.length = cfg.Length
+	case "":
+		return columnRule{}, errors.New("action isn't set for '" + cfg.Column + "' column")
+	default:
+		return columnRule{}, fmt.Errorf("unknown '%s' action for '%s' column. known values ['%s','%s','%s','%s']",
+			cfg.Action, cfg.Column, dropAction, maskAction, hashAction, truncateAction)
+	}
+	return rule, nil
+}
+
+// apply transforms the value, null values and values of columns absent in the row image are kept as is.
+func (c *columnRule) apply(value any) any {
+	if value == nil || value == bean.NotLogged {
+		return value
+	}
+	switch c.action {
+	case maskAction:
+		return c.mask(toString(value))
+	case hashAction:
+		sum := sha256.Sum256([]byte(c.salt + toString(value)))
+		return hex.EncodeToString(sum[:])
+	case truncateAction:
+		return c.truncate(value)
+	default:
+		return value
+	}
+}
+
+// mask replaces the value by the fixed replacement or replaces characters except the first and the last ones by the mask character.
+func (c *columnRule) mask(value string) string {
+	if c.replacement != "" {
+		return c.replacement
+	}
+	runes := []rune(value)
+	if c.keepFirst+c.keepLast >= len(runes) {
+		// the value is too short to hide anything by partial mask
+		return strings.Repeat(c.maskChar, len(runes))
+	}
+	var builder strings.Builder
+	builder.WriteString(string(runes[:c.keepFirst]))
+	builder.WriteString(strings.Repeat(c.maskChar, len(runes)-c.keepFirst-c.keepLast))
+	builder.WriteString(string(runes[len(runes)-c.keepLast:]))
+	return builder.String()
+}
+
+// truncate cuts text and binary values, cut JSON document isn't valid JSON anymore, so it's returned as string.
+func (c *columnRule) truncate(value any) any {
+	switch v := value.(type) {
+	case json.RawMessage:
+		if text := string(v); utf8.RuneCountInString(text) > c.length {
+			return truncateString(text, c.length)
+		}
+		return v
+	case []byte:
+		if utf8.Valid(v) {
+			return []byte(truncateString(string(v), c.length))
+		}
+		if len(v) > c.length {
+			return v[:c.length]
+		}
+		return v
+	case string:
+		return truncateString(v, c.length)
+	default:
+		return value
+	}
+}
+
+func truncateString(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length])
+}
+
+func toString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.RawMessage:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
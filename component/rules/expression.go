@@ -0,0 +1,651 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The filter expression grammar is a small subset of SQL WHERE clause:
+//
+//	expr       = or
+//	or         = and { OR and }
+//	and        = not { AND not }
+//	not        = NOT not | primary
+//	primary    = '(' expr ')' | column predicate
+//	predicate  = op literal | [NOT] IN '(' literal { ',' literal } ')' | [NOT] LIKE string | IS [NOT] NULL
+//	op         = '=' | '!=' | '<>' | '<' | '<=' | '>' | '>='
+//	literal    = string | number | TRUE | FALSE | NULL
+//
+// Column names can be quoted by backticks. Comparison with NULL value is unknown like in SQL,
+// the row matches the expression only when the result is true.
+// Values of numeric columns are compared numerically, DECIMAL values exactly. Values of other columns are compared
+// with quoted literals as strings and with unquoted number literals numerically when the value is a number.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ",", pos: i})
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			start := i
+			var builder strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == r {
+					// doubled quote is escaped quote
+					if i+1 < len(runes) && runes[i+1] == r {
+						builder.WriteRune(r)
+						i += 2
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unclosed quote at %d position", start)
+			}
+			kind := tokenString
+			if r == '`' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, value: builder.String(), pos: start})
+		case strings.ContainsRune("=!<>", r):
+			start := i
+			i++
+			if i < len(runes) && (runes[i] == '=' || (r == '<' && runes[i] == '>')) {
+				i++
+			}
+			op := string(runes[start:i])
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at %d position", start)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op, pos: start})
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at %d position", r, i)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// row provides column values and kinds of the columns for expression evaluation.
+type row func(column string) (any, columnKind)
+
+// columnKind defines how values of the column are compared with literals.
+type columnKind int8
+
+const (
+	// valueColumn values are compared according to their Go types, strings are text
+	valueColumn columnKind = iota
+	// exactColumn values are integers and DECIMAL numbers compared exactly, DECIMAL numbers can be strings
+	exactColumn
+	// floatColumn values are FLOAT and DOUBLE numbers compared as float64
+	floatColumn
+)
+
+// literal is a value of the expression, number is set when the literal is a number or a numeric quoted string.
+type literal struct {
+	text   string
+	quoted bool
+	number *big.Rat
+}
+
+// truth is a result of three-valued logic, unknown is a result of comparison with NULL like in SQL.
+type truth int8
+
+const (
+	falseTruth truth = iota
+	trueTruth
+	unknownTruth
+)
+
+func truthOf(value bool) truth {
+	if value {
+		return trueTruth
+	}
+	return falseTruth
+}
+
+type expression interface {
+	eval(values row) truth
+}
+
+type orExpr struct{ left, right expression }
+
+func (e orExpr) eval(values row) truth {
+	left, right := e.left.eval(values), e.right.eval(values)
+	switch {
+	case left == trueTruth || right == trueTruth:
+		return trueTruth
+	case left == unknownTruth || right == unknownTruth:
+		return unknownTruth
+	default:
+		return falseTruth
+	}
+}
+
+type andExpr struct{ left, right expression }
+
+func (e andExpr) eval(values row) truth {
+	left, right := e.left.eval(values), e.right.eval(values)
+	switch {
+	case left == falseTruth || right == falseTruth:
+		return falseTruth
+	case left == unknownTruth || right == unknownTruth:
+		return unknownTruth
+	default:
+		return trueTruth
+	}
+}
+
+type notExpr struct{ expr expression }
+
+func (e notExpr) eval(values row) truth {
+	switch e.expr.eval(values) {
+	case trueTruth:
+		return falseTruth
+	case falseTruth:
+		return trueTruth
+	default:
+		return unknownTruth
+	}
+}
+
+type compareExpr struct {
+	column string
+	op     string
+	value  *literal
+}
+
+func (e compareExpr) eval(values row) truth {
+	value, kind := values(e.column)
+	if value == nil || e.value == nil {
+		return unknownTruth
+	}
+	c := compare(value, kind, e.value)
+	switch e.op {
+	case "=":
+		return truthOf(c == 0)
+	case "!=", "<>":
+		return truthOf(c != 0)
+	case "<":
+		return truthOf(c < 0)
+	case "<=":
+		return truthOf(c <= 0)
+	case ">":
+		return truthOf(c > 0)
+	case ">=":
+		return truthOf(c >= 0)
+	}
+	return unknownTruth
+}
+
+type inExpr struct {
+	column string
+	values []*literal
+}
+
+func (e inExpr) eval(values row) truth {
+	value, kind := values(e.column)
+	if value == nil {
+		return unknownTruth
+	}
+	result := falseTruth
+	for _, v := range e.values {
+		if v == nil {
+			result = unknownTruth
+			continue
+		}
+		if compare(value, kind, v) == 0 {
+			return trueTruth
+		}
+	}
+	return result
+}
+
+type likeExpr struct {
+	column string
+	regex  *regexp.Regexp
+}
+
+func (e likeExpr) eval(values row) truth {
+	value, _ := values(e.column)
+	if value == nil {
+		return unknownTruth
+	}
+	return truthOf(e.regex.MatchString(toString(value)))
+}
+
+type isNullExpr struct {
+	column string
+}
+
+func (e isNullExpr) eval(values row) truth {
+	value, _ := values(e.column)
+	return truthOf(value == nil)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parseExpression(expr string) (expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing '%s' expression failure: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	result, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing '%s' expression failure: %w", expr, err)
+	}
+	return result, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token when it is the keyword.
+func (p *parser) keyword(keyword string) bool {
+	t := p.peek()
+	if t.kind == tokenIdent && strings.EqualFold(t.value, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) unexpected() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	return fmt.Errorf("unexpected '%s' at %d position", t.value, t.pos)
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if p.keyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, p.unexpected()
+		}
+		p.next()
+		return expr, nil
+	}
+	if p.peek().kind != tokenIdent {
+		return nil, p.unexpected()
+	}
+	column := p.next().value
+	return p.parsePredicate(column)
+}
+
+func (p *parser) parsePredicate(column string) (expression, error) {
+	if p.peek().kind == tokenOperator {
+		op := p.next().value
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{column: column, op: op, value: value}, nil
+	}
+	if p.keyword("IS") {
+		negate := p.keyword("NOT")
+		if !p.keyword("NULL") {
+			return nil, p.unexpected()
+		}
+		return negateIf(isNullExpr{column: column}, negate), nil
+	}
+	negate := p.keyword("NOT")
+	switch {
+	case p.keyword("IN"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return negateIf(inExpr{column: column, values: values}, negate), nil
+	case p.keyword("LIKE"):
+		if p.peek().kind != tokenString {
+			return nil, p.unexpected()
+		}
+		regex, err := likeRegex(p.next().value)
+		if err != nil {
+			return nil, err
+		}
+		return negateIf(likeExpr{column: column, regex: regex}, negate), nil
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseList() ([]*literal, error) {
+	if p.peek().kind != tokenLParen {
+		return nil, p.unexpected()
+	}
+	p.next()
+	var values []*literal
+	for {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		switch p.peek().kind {
+		case tokenComma:
+			p.next()
+		case tokenRParen:
+			p.next()
+			return values, nil
+		default:
+			return nil, p.unexpected()
+		}
+	}
+}
+
+// parseLiteral returns nil for NULL literal.
+func (p *parser) parseLiteral() (*literal, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenString:
+		p.next()
+		number, _ := parseNumber(t.value)
+		return &literal{text: t.value, quoted: true, number: number}, nil
+	case tokenNumber:
+		p.next()
+		number, ok := parseNumber(t.value)
+		if !ok {
+			return nil, fmt.Errorf("incorrect '%s' number at %d position", t.value, t.pos)
+		}
+		return &literal{text: t.value, number: number}, nil
+	case tokenIdent:
+		switch {
+		case p.keyword("NULL"):
+			return nil, nil
+		case p.keyword("TRUE"):
+			return &literal{text: "1", number: big.NewRat(1, 1)}, nil
+		case p.keyword("FALSE"):
+			return &literal{text: "0", number: new(big.Rat)}, nil
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func negateIf(expr expression, negate bool) expression {
+	if negate {
+		return notExpr{expr: expr}
+	}
+	return expr
+}
+
+// likeRegex converts SQL LIKE pattern with `%` and `_` wildcards and `\` escape to regular expression.
+func likeRegex(pattern string) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	builder.WriteString("(?s)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '%':
+			builder.WriteString(".*")
+		case '_':
+			builder.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+			} else {
+				builder.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("$")
+	return regexp.Compile(builder.String())
+}
+
+// compare compares the value with the literal numerically when the column is numeric or the unquoted literal is compared
+// with a number, otherwise their string representations are compared.
+func compare(value any, kind columnKind, lit *literal) int {
+	if lit.number != nil {
+		if x, ok := toNumber(value, kind, !lit.quoted); ok {
+			return x.compare(lit.number)
+		}
+	}
+	return strings.Compare(toString(value), lit.text)
+}
+
+// number is a numeric value, integers and DECIMAL numbers are exact because float64 can't represent them precisely.
+type number struct {
+	float   bool
+	float32 bool
+	f       float64
+	exact   *big.Rat
+}
+
+// compare compares the number with the literal number, float numbers are compared with the nearest float64 of the literal.
+func (n number) compare(y *big.Rat) int {
+	if !n.float {
+		return n.exact.Cmp(y)
+	}
+	f, _ := y.Float64()
+	if n.float32 {
+		f32, _ := y.Float32()
+		f = float64(f32)
+	}
+	switch {
+	case n.f < f:
+		return -1
+	case n.f > f:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// toNumber returns the numeric value of the numeric column, the value of other column is a number when its Go type is numeric.
+// Strings of other columns are numbers only when they are compared with unquoted number literal like in SQL.
+func toNumber(value any, kind columnKind, unquoted bool) (number, bool) {
+	switch v := value.(type) {
+	case int:
+		return exactNumber(new(big.Rat).SetInt64(int64(v)))
+	case int8:
+		return exactNumber(new(big.Rat).SetInt64(int64(v)))
+	case int16:
+		return exactNumber(new(big.Rat).SetInt64(int64(v)))
+	case int32:
+		return exactNumber(new(big.Rat).SetInt64(int64(v)))
+	case int64:
+		return exactNumber(new(big.Rat).SetInt64(v))
+	case uint:
+		return exactNumber(new(big.Rat).SetUint64(uint64(v)))
+	case uint8:
+		return exactNumber(new(big.Rat).SetUint64(uint64(v)))
+	case uint16:
+		return exactNumber(new(big.Rat).SetUint64(uint64(v)))
+	case uint32:
+		return exactNumber(new(big.Rat).SetUint64(uint64(v)))
+	case uint64:
+		return exactNumber(new(big.Rat).SetUint64(v))
+	case float32:
+		n, ok := floatNumber(float64(v))
+		n.float32 = true
+		return n, ok
+	case float64:
+		return floatNumber(v)
+	case json.Number:
+		// DECIMAL values in number format
+		return textNumber(string(v), kind)
+	case string:
+		// DECIMAL values in string format
+		if kind == valueColumn && !unquoted {
+			return number{}, false
+		}
+		return textNumber(v, kind)
+	default:
+		return number{}, false
+	}
+}
+
+func exactNumber(v *big.Rat) (number, bool) {
+	return number{exact: v}, true
+}
+
+func floatNumber(v float64) (number, bool) {
+	return number{float: true, f: v}, !math.IsNaN(v)
+}
+
+func textNumber(text string, kind columnKind) (number, bool) {
+	if kind == floatColumn {
+		if !numberRegex.MatchString(text) {
+			return number{}, false
+		}
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return number{}, false
+		}
+		return floatNumber(f)
+	}
+	v, ok := parseNumber(text)
+	if !ok {
+		return number{}, false
+	}
+	return exactNumber(v)
+}
+
+// numberRegex matches decimal numbers, the exponent is limited because big exponent makes exact number huge.
+var numberRegex = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d{1,3})?$`)
+
+// parseNumber parses the decimal number exactly, special values like 'inf' and hexadecimal numbers aren't numbers.
+func parseNumber(text string) (*big.Rat, bool) {
+	if !numberRegex.MatchString(text) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(text)
+}
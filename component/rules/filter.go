@@ -0,0 +1,211 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+)
+
+type Operation int
+
+const (
+	Insert Operation = iota
+	Update
+	Delete
+)
+
+func (o Operation) String() string {
+	switch o {
+	case Insert:
+		return "INSERT"
+	case Update:
+		return "UPDATE"
+	case Delete:
+		return "DELETE"
+	default:
+		return fmt.Sprintf("Operation(%d)", int(o))
+	}
+}
+
+type image int
+
+const (
+	anyImage image = iota
+	beforeImage
+	afterImage
+)
+
+type rowFilter struct {
+	operations map[Operation]struct{}
+	image      image
+	expr       expression
+}
+
+func newRowFilter(cfg conf.RowFilter) (rowFilter, error) {
+	if strings.TrimSpace(cfg.Expression) == "" {
+		return rowFilter{}, errors.New("filter expression isn't specified")
+	}
+	expr, err := parseExpression(cfg.Expression)
+	if err != nil {
+		return rowFilter{}, err
+	}
+	filter := rowFilter{expr: expr}
+	if len(cfg.Operations) != 0 {
+		filter.operations = make(map[Operation]struct{}, len(cfg.Operations))
+		for _, name := range cfg.Operations {
+			switch strings.ToUpper(name) {
+			case "INSERT":
+				filter.operations[Insert] = struct{}{}
+			case "UPDATE":
+				filter.operations[Update] = struct{}{}
+			case "DELETE":
+				filter.operations[Delete] = struct{}{}
+			default:
+				return rowFilter{}, fmt.Errorf("unknown '%s' operation", name)
+			}
+		}
+	}
+	switch strings.ToLower(cfg.Image) {
+	case "", "any":
+		filter.image = anyImage
+	case "before":
+		filter.image = beforeImage
+	case "after":
+		filter.image = afterImage
+	default:
+		return rowFilter{}, fmt.Errorf("unknown '%s' image", cfg.Image)
+	}
+	return filter, nil
+}
+
+func (f *rowFilter) appliesTo(operation Operation) bool {
+	if f.operations == nil {
+		return true
+	}
+	_, ok := f.operations[operation]
+	return ok
+}
+
+// Filter returns rows matching all filters of the table configured for the operation, quantity of filtered out rows
+// and quantity of updates changing only noise columns of the table, such updates are filtered out too.
+// Rows of update are pairs of before and after images, so the pair is kept or filtered out together.
+// Column types of table map event define how values are compared, values are compared by their Go types when types are unknown.
+func (r *Rules) Filter(schema string, table string, operation Operation, fields []string, types []byte, rows [][]any) ([][]any, int, int) {
+	tr := r.find(schema, table)
+	if tr == nil {
+		return rows, 0, 0
+	}
+	filters := make([]*rowFilter, 0, len(tr.filters))
+	for i := range tr.filters {
+		if tr.filters[i].appliesTo(operation) {
+			filters = append(filters, &tr.filters[i])
+		}
+	}
+	noise := operation == Update && len(tr.updates.noise) != 0
+	if len(filters) == 0 && !noise {
+		return rows, 0, 0
+	}
+
+	indexes := make(map[string]int, len(fields))
+	for i, field := range fields {
+		indexes[field] = i
+	}
+	// column absent in the after image of update isn't changed, so its before image value is used when it is logged.
+	// Columns absent in both images are treated as null.
+	values := func(values []any, before []any) row {
+		return func(column string) (any, columnKind) {
+			i, ok := indexes[column]
+			if !ok || i >= len(values) {
+				return nil, valueColumn
+			}
+			kind := valueColumn
+			if i < len(types) {
+				kind = kindOf(types[i])
+			}
+			if values[i] == bean.NotLogged {
+				if i < len(before) && before[i] != bean.NotLogged {
+					return before[i], kind
+				}
+				return nil, kind
+			}
+			return values[i], kind
+		}
+	}
+
+	step := 1
+	if operation == Update {
+		step = 2
+	}
+	result := make([][]any, 0, len(rows))
+	suppressed := 0
+	for i := 0; i+step <= len(rows); i += step {
+		var before []any
+		if operation == Update {
+			before = rows[i]
+		}
+		if noise && tr.updates.isNoise(fields, rows[i], rows[i+1]) {
+			suppressed++
+			continue
+		}
+		if matchAll(filters, operation, values(rows[i], nil), values(rows[i+step-1], before)) {
+			result = append(result, rows[i:i+step]...)
+		}
+	}
+	filtered := len(rows) - len(result)
+	if operation == Update {
+		filtered /= 2
+	}
+	return result, filtered - suppressed, suppressed
+}
+
+func matchAll(filters []*rowFilter, operation Operation, before row, after row) bool {
+	for _, filter := range filters {
+		var matched bool
+		switch {
+		case operation != Update:
+			matched = filter.expr.eval(before) == trueTruth
+		case filter.image == beforeImage:
+			matched = filter.expr.eval(before) == trueTruth
+		case filter.image == afterImage:
+			matched = filter.expr.eval(after) == trueTruth
+		default:
+			matched = filter.expr.eval(before) == trueTruth || filter.expr.eval(after) == trueTruth
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func kindOf(columnType byte) columnKind {
+	switch columnType {
+	case mysql.MYSQL_TYPE_TINY, mysql.MYSQL_TYPE_SHORT, mysql.MYSQL_TYPE_INT24, mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_LONGLONG,
+		mysql.MYSQL_TYPE_YEAR, mysql.MYSQL_TYPE_DECIMAL, mysql.MYSQL_TYPE_NEWDECIMAL:
+		return exactColumn
+	case mysql.MYSQL_TYPE_FLOAT, mysql.MYSQL_TYPE_DOUBLE:
+		return floatColumn
+	default:
+		return valueColumn
+	}
+}
@@ -0,0 +1,316 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/rules"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/values"
+)
+
+func TestFilterExpressions(t *testing.T) {
+	fields := []string{"id", "status", "amount", "note"}
+	rows := [][]any{
+		{int64(1), "NEW", "10.50", nil},
+		{int64(2), "DONE", "200.00", "it's done"},
+		{int64(3), "CANCELLED", "0.00", "test order"},
+	}
+	tests := []struct {
+		expression string
+		ids        []int64
+	}{
+		{expression: "id = 2", ids: []int64{2}},
+		{expression: "id != 2", ids: []int64{1, 3}},
+		{expression: "id <> 2 AND amount >= 10", ids: []int64{1}},
+		{expression: "amount > 100 OR status = 'NEW'", ids: []int64{1, 2}},
+		{expression: "status IN ('NEW', 'DONE')", ids: []int64{1, 2}},
+		{expression: "status NOT IN ('NEW', 'DONE')", ids: []int64{3}},
+		{expression: "note IS NULL", ids: []int64{1}},
+		{expression: "note IS NOT NULL", ids: []int64{2, 3}},
+		{expression: "note = NULL", ids: nil},
+		{expression: "note LIKE 'test%'", ids: []int64{3}},
+		{expression: "note NOT LIKE '%done'", ids: []int64{3}},
+		{expression: "note = 'it''s done'", ids: []int64{2}},
+		{expression: "NOT (id = 1 OR id = 3)", ids: []int64{2}},
+		{expression: "`status` = \"DONE\"", ids: []int64{2}},
+		{expression: "status = 'NEW' or status = 'DONE' and amount < 100", ids: []int64{1}},
+		{expression: "unknown IS NULL", ids: []int64{1, 2, 3}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			r, err := rules.New([]conf.TableRules{
+				{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{{Expression: tc.expression}}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, filtered, _ := r.Filter("shop", "orders", rules.Insert, fields, nil, rows)
+			var ids []int64
+			for _, row := range result {
+				ids = append(ids, row[0].(int64))
+			}
+			if !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("%v rows expected, got %v", tc.ids, ids)
+			}
+			if filtered != len(rows)-len(tc.ids) {
+				t.Fatalf("%d filtered rows expected, got %d", len(rows)-len(tc.ids), filtered)
+			}
+		})
+	}
+}
+
+func TestFilterUpdateImages(t *testing.T) {
+	fields := []string{"id", "status"}
+	rows := [][]any{
+		{int64(1), "NEW"}, {int64(1), "DONE"},
+		{int64(2), "DONE"}, {int64(2), "ARCHIVED"},
+		{int64(3), "NEW"}, {int64(3), "NEW"},
+	}
+	tests := []struct {
+		image string
+		ids   []int64
+	}{
+		{image: "Before", ids: []int64{2}},
+		{image: "After", ids: []int64{1}},
+		{image: "", ids: []int64{1, 2}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			r, err := rules.New([]conf.TableRules{
+				{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{
+					{Operations: []string{"UPDATE"}, Image: tc.image, Expression: "status = 'DONE'"},
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, filtered, _ := r.Filter("shop", "orders", rules.Update, fields, nil, rows)
+			if len(result)%2 != 0 {
+				t.Fatalf("pairs of images expected, got %v", result)
+			}
+			var ids []int64
+			for i := 0; i < len(result); i += 2 {
+				if result[i][0] != result[i+1][0] {
+					t.Fatalf("images of the same row expected, got %v", result)
+				}
+				ids = append(ids, result[i][0].(int64))
+			}
+			if !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("%v rows expected, got %v", tc.ids, ids)
+			}
+			if filtered != 3-len(tc.ids) {
+				t.Fatalf("%d filtered rows expected, got %d", 3-len(tc.ids), filtered)
+			}
+
+			// the filter isn't configured for inserts
+			result, filtered, _ = r.Filter("shop", "orders", rules.Insert, fields, nil, rows)
+			if len(result) != len(rows) || filtered != 0 {
+				t.Fatalf("all rows expected, got %v", result)
+			}
+		})
+	}
+}
+
+func TestFilterNotLoggedColumns(t *testing.T) {
+	fields := []string{"id", "status"}
+	// binlog_row_image=MINIMAL: before image has primary key, after image has changed columns only
+	rows := [][]any{
+		{int64(1), bean.NotLogged}, {bean.NotLogged, "DONE"},
+		{int64(2), bean.NotLogged}, {bean.NotLogged, "NEW"},
+		{int64(3), "DONE"}, {bean.NotLogged, bean.NotLogged},
+	}
+	r, err := rules.New([]conf.TableRules{
+		{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{
+			{Image: "After", Expression: "status = 'DONE' AND id IS NOT NULL"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, filtered, _ := r.Filter("shop", "orders", rules.Update, fields, nil, rows)
+	expected := [][]any{rows[0], rows[1], rows[4], rows[5]}
+	if !reflect.DeepEqual(result, expected) || filtered != 1 {
+		t.Fatalf("%v expected, got %v, filtered %d", expected, result, filtered)
+	}
+
+	result, filtered, _ = r.Filter("shop", "orders", rules.Insert, fields, nil, [][]any{{int64(1), bean.NotLogged}})
+	if len(result) != 0 || filtered != 1 {
+		t.Fatalf("absent column is expected to be treated as null, got %v", result)
+	}
+}
+
+func TestFilterNoiseUpdates(t *testing.T) {
+	fields := []string{"id", "status", "updated_at", "version"}
+	rows := [][]any{
+		{int64(1), "NEW", "2024-02-12 14:30:00", int64(1)}, {int64(1), "NEW", "2024-02-12 14:31:00", int64(2)},
+		{int64(2), "NEW", "2024-02-12 14:30:00", int64(1)}, {int64(2), "DONE", "2024-02-12 14:31:00", int64(2)},
+		{int64(3), "NEW", "2024-02-12 14:30:00", int64(1)}, {int64(3), "NEW", "2024-02-12 14:30:00", int64(1)},
+	}
+	r, err := rules.New([]conf.TableRules{
+		{Schema: "shop", Table: "orders", Updates: conf.UpdateRules{Noise: []string{"updated_at", "ver*"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, filtered, noise := r.Filter("shop", "orders", rules.Update, fields, nil, rows)
+	expected := rows[2:]
+	if !reflect.DeepEqual(result, expected) || filtered != 0 || noise != 1 {
+		t.Fatalf("%v expected, got %v, filtered %d, noise %d", expected, result, filtered, noise)
+	}
+	if format := r.UpdateFormat("shop", "orders"); format.Diff {
+		t.Fatalf("full update format expected, got %+v", format)
+	}
+}
+
+func TestFilterConvertedNumbers(t *testing.T) {
+	converter, err := values.New(conf.Values{Decimal: "number"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tableMap := &replication.TableMapEvent{
+		Schema:           []byte("shop"),
+		Table:            []byte("orders"),
+		ColumnCount:      3,
+		ColumnType:       []byte{mysql.MYSQL_TYPE_LONGLONG, mysql.MYSQL_TYPE_NEWDECIMAL, mysql.MYSQL_TYPE_LONGLONG},
+		ColumnMeta:       []uint16{0, 10<<8 | 2, 0},
+		SignednessBitmap: []byte{0b00100000},
+	}
+	fields := []string{"id", "amount", "counter"}
+	rows, err := converter.Convert(tableMap, fields, [][]any{
+		{int64(1), "99.5", int64(-1)},
+		{int64(9007199254740993), "1000", int64(5)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rows[0][1].(json.Number); !ok {
+		t.Fatalf("DECIMAL value is expected to be converted to number, got %T", rows[0][1])
+	}
+	tests := []struct {
+		expression string
+		ids        []any
+	}{
+		{expression: "amount >= 100", ids: []any{int64(9007199254740993)}},
+		{expression: "amount < 100.0", ids: []any{int64(1)}},
+		// 2^53 + 1 isn't representable by float64
+		{expression: "id > 9007199254740992", ids: []any{int64(9007199254740993)}},
+		{expression: "id = 9007199254740992", ids: nil},
+		// unsigned column value 2^64 - 1
+		{expression: "counter = 18446744073709551615", ids: []any{int64(1)}},
+		{expression: "counter > 9223372036854775807", ids: []any{int64(1)}},
+		{expression: "counter > 1.5", ids: []any{int64(1), int64(9007199254740993)}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			r, err := rules.New([]conf.TableRules{
+				{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{{Expression: tc.expression}}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, filtered, _ := r.Filter("shop", "orders", rules.Insert, fields, tableMap.ColumnType, rows)
+			var ids []any
+			for _, row := range result {
+				ids = append(ids, row[0])
+			}
+			if !reflect.DeepEqual(ids, tc.ids) || filtered != len(rows)-len(tc.ids) {
+				t.Fatalf("%v rows expected, got %v, filtered %d", tc.ids, ids, filtered)
+			}
+		})
+	}
+}
+
+func TestFilterColumnTypes(t *testing.T) {
+	fields := []string{"id", "code", "amount", "ratio"}
+	types := []byte{mysql.MYSQL_TYPE_LONGLONG, mysql.MYSQL_TYPE_VARCHAR, mysql.MYSQL_TYPE_NEWDECIMAL, mysql.MYSQL_TYPE_DOUBLE}
+	// DECIMAL(65,30) values in string format
+	rows := [][]any{
+		{int64(1), "1234", "1.000000000000000000010000000000", 0.1},
+		{int64(2), "01234", "1.000000000000000000020000000000", 0.5},
+		{int64(3), "10", "0.000000000000000000000000000000", 0.5},
+		{int64(4), "9", "0.000000000000000000000000000000", 0.5},
+		{int64(5), "1e3", "0.000000000000000000000000000000", 0.5},
+		{int64(6), "Infinity", "0.000000000000000000000000000000", 0.5},
+	}
+	tests := []struct {
+		expression string
+		ids        []int64
+	}{
+		// quoted literals are compared with strings as strings
+		{expression: "code = '01234'", ids: []int64{2}},
+		{expression: "code = '1000'", ids: nil},
+		{expression: "code = 'inf'", ids: nil},
+		{expression: "code < '9'", ids: []int64{1, 2, 3, 5}},
+		{expression: "code > '9'", ids: []int64{6}},
+		{expression: "code IN ('1234', '10')", ids: []int64{1, 3}},
+		// unquoted numbers are compared with numeric strings numerically
+		{expression: "code = 1234", ids: []int64{1, 2}},
+		{expression: "code = 1000", ids: []int64{5}},
+		// DECIMAL values differ in the 20th digit
+		{expression: "amount = 1.00000000000000000001", ids: []int64{1}},
+		{expression: "amount > '1.00000000000000000001'", ids: []int64{2}},
+		{expression: "amount >= 1", ids: []int64{1, 2}},
+		{expression: "ratio = 0.1", ids: []int64{1}},
+		{expression: "id = '01'", ids: []int64{1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			r, err := rules.New([]conf.TableRules{
+				{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{{Expression: tc.expression}}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, _, _ := r.Filter("shop", "orders", rules.Insert, fields, types, rows)
+			var ids []int64
+			for _, row := range result {
+				ids = append(ids, row[0].(int64))
+			}
+			if !reflect.DeepEqual(ids, tc.ids) {
+				t.Fatalf("%v rows expected, got %v", tc.ids, ids)
+			}
+		})
+	}
+}
+
+func TestInvalidFilter(t *testing.T) {
+	for _, filter := range []conf.RowFilter{
+		{Expression: ""},
+		{Expression: "id ="},
+		{Expression: "id = 1 AND"},
+		{Expression: "(id = 1"},
+		{Expression: "id IN 1"},
+		{Expression: "name = 'unclosed"},
+		{Expression: "id ! 1"},
+		{Expression: "id = 1.2.3"},
+		{Expression: "id = 1", Operations: []string{"TRUNCATE"}},
+		{Expression: "id = 1", Image: "middle"},
+	} {
+		t.Run(filter.Expression, func(t *testing.T) {
+			if _, err := rules.New([]conf.TableRules{{Schema: "shop", Table: "orders", Filters: []conf.RowFilter{filter}}}); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
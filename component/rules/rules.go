@@ -0,0 +1,141 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+type tableRules struct {
+	schema  database.NameMatcher
+	table   database.NameMatcher
+	columns []columnRule
+	filters []rowFilter
+	updates updateRules
+}
+
+// Rules filters rows and transforms row values of observed tables before they are put to a message.
+// The first rules matching schema and table names are applied.
+type Rules struct {
+	tables []tableRules
+}
+
+func New(cfg []conf.TableRules) (*Rules, error) {
+	rules := &Rules{tables: make([]tableRules, 0, len(cfg))}
+	for _, tableCfg := range cfg {
+		schema, err := database.NewNameMatcher(tableCfg.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("creating schema matcher failure: %w", err)
+		}
+		table, err := database.NewNameMatcher(tableCfg.Table)
+		if err != nil {
+			return nil, fmt.Errorf("creating table matcher for '%s' schema failure: %w", tableCfg.Schema, err)
+		}
+		columns := make([]columnRule, 0, len(tableCfg.Columns))
+		for _, columnCfg := range tableCfg.Columns {
+			column, err := newColumnRule(columnCfg)
+			if err != nil {
+				return nil, fmt.Errorf("creating column rule for %s.%s table failure: %w", tableCfg.Schema, tableCfg.Table, err)
+			}
+			columns = append(columns, column)
+		}
+		filters := make([]rowFilter, 0, len(tableCfg.Filters))
+		for _, filterCfg := range tableCfg.Filters {
+			filter, err := newRowFilter(filterCfg)
+			if err != nil {
+				return nil, fmt.Errorf("creating row filter for %s.%s table failure: %w", tableCfg.Schema, tableCfg.Table, err)
+			}
+			filters = append(filters, filter)
+		}
+		updates, err := newUpdateRules(tableCfg.Updates)
+		if err != nil {
+			return nil, fmt.Errorf("creating update rules for %s.%s table failure: %w", tableCfg.Schema, tableCfg.Table, err)
+		}
+		rules.tables = append(rules.tables, tableRules{schema: schema, table: table, columns: columns, filters: filters, updates: updates})
+	}
+	return rules, nil
+}
+
+func (r *Rules) find(schema string, table string) *tableRules {
+	if r == nil {
+		return nil
+	}
+	for i := range r.tables {
+		if r.tables[i].schema.Matches(schema) && r.tables[i].table.Matches(table) {
+			return &r.tables[i]
+		}
+	}
+	return nil
+}
+
+// Apply returns fields and rows transformed by column rules of the table, the passed rows aren't modified.
+func (r *Rules) Apply(schema string, table string, fields []string, rows [][]any) ([]string, [][]any) {
+	tr := r.find(schema, table)
+	if tr == nil || len(tr.columns) == 0 {
+		return fields, rows
+	}
+	indexes := make([]int, 0, len(fields))
+	actions := make([]*columnRule, 0, len(fields))
+	changed := false
+	for i, field := range fields {
+		rule := tr.column(field)
+		if rule != nil {
+			changed = true
+			if rule.action == dropAction {
+				continue
+			}
+		}
+		indexes = append(indexes, i)
+		actions = append(actions, rule)
+	}
+	if !changed {
+		return fields, rows
+	}
+
+	resultFields := make([]string, len(indexes))
+	for i, index := range indexes {
+		resultFields[i] = fields[index]
+	}
+	resultRows := make([][]any, len(rows))
+	for i, row := range rows {
+		resultRow := make([]any, len(indexes))
+		for j, index := range indexes {
+			if index >= len(row) {
+				continue
+			}
+			value := row[index]
+			if actions[j] != nil {
+				value = actions[j].apply(value)
+			}
+			resultRow[j] = value
+		}
+		resultRows[i] = resultRow
+	}
+	return resultFields, resultRows
+}
+
+func (tr *tableRules) column(name string) *columnRule {
+	for i := range tr.columns {
+		if tr.columns[i].column.Matches(name) {
+			return &tr.columns[i]
+		}
+	}
+	return nil
+}
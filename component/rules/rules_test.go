@@ -0,0 +1,178 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/rules"
+)
+
+func TestApplyColumnRules(t *testing.T) {
+	r, err := rules.New([]conf.TableRules{
+		{
+			Schema: "shop",
+			Table:  "customers",
+			Columns: []conf.ColumnRule{
+				{Column: "password", Action: "drop"},
+				{Column: "card", Action: "mask", KeepLast: 4},
+				{Column: "phone", Action: "mask", Replacement: "<hidden>"},
+				{Column: "email", Action: "hash", Salt: "salt"},
+				{Column: "note*", Action: "truncate", Length: 5},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"id", "password", "card", "phone", "email", "note", "note_bin"}
+	rows := [][]any{
+		{int64(1), "secret", "4111111111111111", "+1234567", "user@example.com", "привет мир", []byte("long text")},
+		{int64(2), nil, "123", nil, nil, "short", []byte{0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa}},
+	}
+	original := slices.Clone(rows[0])
+
+	resultFields, resultRows := r.Apply("shop", "customers", fields, rows)
+
+	sum := sha256.Sum256([]byte("salt" + "user@example.com"))
+	expectedFields := []string{"id", "card", "phone", "email", "note", "note_bin"}
+	expectedRows := [][]any{
+		{int64(1), "************1111", "<hidden>", hex.EncodeToString(sum[:]), "приве", []byte("long ")},
+		{int64(2), "***", nil, nil, "short", []byte{0xff, 0xfe, 0xfd, 0xfc, 0xfb}},
+	}
+	if !slices.Equal(resultFields, expectedFields) {
+		t.Fatalf("%v fields expected, got %v", expectedFields, resultFields)
+	}
+	if !reflect.DeepEqual(resultRows, expectedRows) {
+		t.Fatalf("%v rows expected, got %v", expectedRows, resultRows)
+	}
+	if !reflect.DeepEqual(rows[0], original) {
+		t.Fatalf("source rows aren't expected to be modified, got %v", rows[0])
+	}
+}
+
+func TestApplyColumnRulesToJSON(t *testing.T) {
+	r, err := rules.New([]conf.TableRules{
+		{
+			Schema: "shop",
+			Table:  "customers",
+			Columns: []conf.ColumnRule{
+				{Column: "profile", Action: "mask", KeepFirst: 2},
+				{Column: "secret", Action: "hash", Salt: "salt"},
+				{Column: "doc*", Action: "truncate", Length: 8},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"profile", "secret", "doc", "doc_short"}
+	rows := [][]any{{json.RawMessage(`{"a":1}`), json.RawMessage(`"key"`), json.RawMessage(`{"name":"value"}`), json.RawMessage(`[1,2]`)}}
+
+	_, resultRows := r.Apply("shop", "customers", fields, rows)
+
+	sum := sha256.Sum256([]byte("salt" + `"key"`))
+	expected := []any{`{"*****`, hex.EncodeToString(sum[:]), `{"name":`, json.RawMessage(`[1,2]`)}
+	if !reflect.DeepEqual(resultRows[0], expected) {
+		t.Fatalf("%v expected, got %v", expected, resultRows[0])
+	}
+	if _, err := json.Marshal(resultRows[0]); err != nil {
+		t.Fatalf("serializable values expected: %v", err)
+	}
+}
+
+func TestApplyWithoutRules(t *testing.T) {
+	r, err := rules.New([]conf.TableRules{
+		{Schema: "tenant_*", Table: "accounts", Columns: []conf.ColumnRule{{Column: "password", Action: "drop"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{"id", "password"}
+	rows := [][]any{{int64(1), "secret"}}
+
+	for _, table := range []string{"orders", "accounts"} {
+		resultFields, resultRows := r.Apply("shop", table, fields, rows)
+		if !slices.Equal(resultFields, fields) || !reflect.DeepEqual(resultRows, rows) {
+			t.Fatalf("rows without changes expected, got %v %v", resultFields, resultRows)
+		}
+	}
+	resultFields, _ := r.Apply("tenant_001", "accounts", fields, rows)
+	if !slices.Equal(resultFields, []string{"id"}) {
+		t.Fatalf("rule matched by pattern expected, got %v", resultFields)
+	}
+
+	var nilRules *rules.Rules
+	if resultFields, _ := nilRules.Apply("shop", "accounts", fields, rows); !slices.Equal(resultFields, fields) {
+		t.Fatalf("nil rules aren't expected to change fields, got %v", resultFields)
+	}
+}
+
+func TestNewRulesIncorrect(t *testing.T) {
+	tests := []struct {
+		name string
+		rule conf.ColumnRule
+	}{
+		{name: "unknown action", rule: conf.ColumnRule{Column: "c", Action: "encrypt"}},
+		{name: "without action", rule: conf.ColumnRule{Column: "c"}},
+		{name: "hash without salt", rule: conf.ColumnRule{Column: "c", Action: "hash"}},
+		{name: "truncate without length", rule: conf.ColumnRule{Column: "c", Action: "truncate"}},
+		{name: "negative mask", rule: conf.ColumnRule{Column: "c", Action: "mask", KeepFirst: -1}},
+		{name: "empty column", rule: conf.ColumnRule{Action: "drop"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := rules.New([]conf.TableRules{{Schema: "s", Table: "t", Columns: []conf.ColumnRule{tc.rule}}}); err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
+
+func TestUpdateRules(t *testing.T) {
+	r, err := rules.New([]conf.TableRules{
+		{Schema: "shop", Table: "orders", Updates: conf.UpdateRules{Format: "diff", Key: []string{"order_id"}, IncludeAfter: true}},
+		{Schema: "shop", Table: "*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := rules.UpdateFormat{Diff: true, Key: []string{"order_id"}, IncludeAfter: true}
+	if format := r.UpdateFormat("shop", "orders"); !reflect.DeepEqual(format, expected) {
+		t.Fatalf("%+v expected, got %+v", expected, format)
+	}
+	if format := r.UpdateFormat("shop", "items"); format.Diff {
+		t.Fatalf("full format expected, got %+v", format)
+	}
+
+	for _, updates := range []conf.UpdateRules{
+		{Format: "patch"},
+		{Key: []string{"id"}},
+		{IncludeAfter: true},
+		{Noise: []string{""}},
+	} {
+		if _, err := rules.New([]conf.TableRules{{Schema: "s", Table: "t", Updates: updates}}); err == nil {
+			t.Fatalf("error expected for %+v", updates)
+		}
+	}
+}
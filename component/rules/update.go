@@ -0,0 +1,103 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/bean"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	fullUpdateFormat = "full"
+	diffUpdateFormat = "diff"
+)
+
+// UpdateFormat describes message representation of updated rows.
+type UpdateFormat struct {
+	// Diff is true when the key and changed columns are published instead of complete before and after images.
+	Diff bool
+	// Key is the list of key columns configured for the table, the primary key is used when it is empty.
+	Key []string
+	// IncludeAfter is true when the complete after image is published with the changed columns.
+	IncludeAfter bool
+}
+
+type updateRules struct {
+	format UpdateFormat
+	noise  []database.NameMatcher
+}
+
+func newUpdateRules(cfg conf.UpdateRules) (updateRules, error) {
+	var rules updateRules
+	switch strings.ToLower(cfg.Format) {
+	case "", fullUpdateFormat:
+		if len(cfg.Key) != 0 || cfg.IncludeAfter {
+			return updateRules{}, fmt.Errorf("key and after image options require '%s' update format", diffUpdateFormat)
+		}
+	case diffUpdateFormat:
+		rules.format = UpdateFormat{Diff: true, Key: cfg.Key, IncludeAfter: cfg.IncludeAfter}
+	default:
+		return updateRules{}, fmt.Errorf("unknown '%s' update format", cfg.Format)
+	}
+	for _, pattern := range cfg.Noise {
+		matcher, err := database.NewNameMatcher(pattern)
+		if err != nil {
+			return updateRules{}, fmt.Errorf("creating noise column matcher failure: %w", err)
+		}
+		rules.noise = append(rules.noise, matcher)
+	}
+	return rules, nil
+}
+
+// UpdateFormat returns representation of updated rows of the table.
+func (r *Rules) UpdateFormat(schema string, table string) UpdateFormat {
+	tr := r.find(schema, table)
+	if tr == nil {
+		return UpdateFormat{}
+	}
+	return tr.updates.format
+}
+
+// isNoise returns true when only noise columns are changed by the update, update without changed columns isn't noise.
+func (u *updateRules) isNoise(fields []string, before []any, after []any) bool {
+	if len(u.noise) == 0 {
+		return false
+	}
+	changed := bean.ChangedColumns(before, after)
+	if len(changed) == 0 {
+		return false
+	}
+	for _, index := range changed {
+		if index >= len(fields) || !u.matchesNoise(fields[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (u *updateRules) matchesNoise(column string) bool {
+	for _, matcher := range u.noise {
+		if matcher.Matches(column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+const (
+	binaryCharset  = "binary"
+	utf8mb4Charset = "utf8mb4"
+)
+
+// encodings maps MySQL character set names to decoders, nil means that the value is UTF-8 already.
+var encodings = map[string]encoding.Encoding{
+	"utf8":     nil,
+	"utf8mb3":  nil,
+	"utf8mb4":  nil,
+	"ascii":    nil,
+	"latin1":   charmap.Windows1252, // MySQL latin1 is cp1252 actually
+	"latin2":   charmap.ISO8859_2,
+	"latin5":   charmap.ISO8859_9,
+	"latin7":   charmap.ISO8859_13,
+	"greek":    charmap.ISO8859_7,
+	"hebrew":   charmap.ISO8859_8,
+	"cp1250":   charmap.Windows1250,
+	"cp1251":   charmap.Windows1251,
+	"cp1256":   charmap.Windows1256,
+	"cp1257":   charmap.Windows1257,
+	"cp850":    charmap.CodePage850,
+	"cp852":    charmap.CodePage852,
+	"cp866":    charmap.CodePage866,
+	"koi8r":    charmap.KOI8R,
+	"koi8u":    charmap.KOI8U,
+	"macroman": charmap.Macintosh,
+	"tis620":   charmap.Windows874,
+	"gbk":      simplifiedchinese.GBK,
+	"gb2312":   simplifiedchinese.GBK,
+	"gb18030":  simplifiedchinese.GB18030,
+	"big5":     traditionalchinese.Big5,
+	"sjis":     japanese.ShiftJIS,
+	"cp932":    japanese.ShiftJIS,
+	"ujis":     japanese.EUCJP,
+	"eucjpms":  japanese.EUCJP,
+	"euckr":    korean.EUCKR,
+	"ucs2":     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"utf16":    unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"utf16le":  unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf32":    utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM),
+}
+
+// decoder converts text of a character set to UTF-8 string.
+type decoder struct {
+	charset  string
+	encoding encoding.Encoding
+}
+
+func newDecoder(name string) (decoder, error) {
+	name = strings.ToLower(name)
+	if name == binaryCharset {
+		return decoder{charset: name}, nil
+	}
+	enc, ok := encodings[name]
+	if !ok {
+		return decoder{}, fmt.Errorf("unsupported '%s' charset", name)
+	}
+	return decoder{charset: name, encoding: enc}, nil
+}
+
+// collationCharset returns name of the character set of the collation written to table map event.
+func collationCharset(id uint64) (string, bool) {
+	collation, err := charset.GetCollationByID(int(id))
+	if err != nil {
+		return "", false
+	}
+	return collation.CharsetName, true
+}
+
+func (d decoder) binary() bool {
+	return d.charset == binaryCharset
+}
+
+func (d decoder) decode(data []byte) (string, error) {
+	if d.encoding == nil {
+		return string(data), nil
+	}
+	result, err := d.encoding.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("decoding %s text failure: %w", d.charset, err)
+	}
+	return string(result), nil
+}
@@ -0,0 +1,90 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/json"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+// JSONDiff is a partial update of JSON column written by server with binlog_row_value_options=PARTIAL_JSON option.
+// Value is absent for Remove operation.
+type JSONDiff struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+// jsonConversion converts MySQL JSON column values already decoded from binary format to JSON text.
+// The document is embedded into message when embed is true, otherwise it is published as string.
+func jsonConversion(embed bool) conversion {
+	document := func(text string) any {
+		if !embed {
+			return text
+		}
+		return embedJSON(text)
+	}
+	return func(value any) (any, error) {
+		switch v := value.(type) {
+		case string:
+			return document(v), nil
+		case []byte:
+			// server writes empty document when NULL is inserted into NOT NULL column in non-strict mode
+			if len(v) == 0 {
+				return document("null"), nil
+			}
+			return document(string(v)), nil
+		case *replication.JsonDiff:
+			diff := JSONDiff{Op: v.Op.String(), Path: v.Path}
+			if v.Op != replication.JsonDiffOperationRemove {
+				diff.Value = document(v.Value)
+			}
+			return diff, nil
+		default:
+			return value, nil
+		}
+	}
+}
+
+// embedJSON returns the document as raw JSON, invalid document is kept as string.
+func embedJSON(text string) any {
+	if !json.Valid([]byte(text)) {
+		return text
+	}
+	return json.RawMessage(text)
+}
+
+// jsonTextConversion embeds JSON documents stored in character columns, for example, MariaDB JSON columns are LONGTEXT ones.
+func jsonTextConversion(text conversion) conversion {
+	return func(value any) (any, error) {
+		if text != nil {
+			var err error
+			if value, err = text(value); err != nil {
+				return nil, err
+			}
+		}
+		switch v := value.(type) {
+		case string:
+			return embedJSON(v), nil
+		case []byte:
+			return embedJSON(string(v)), nil
+		default:
+			return value, nil
+		}
+	}
+}
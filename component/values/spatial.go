@@ -0,0 +1,361 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// spatial formats, binary format keeps SRID and WKB bytes written by server
+	wktFormat     = "wkt"
+	geoJSONFormat = "geojson"
+	// VECTOR format, binary format keeps bytes written by server
+	arrayFormat = "array"
+
+	sridSize = 4
+)
+
+const (
+	wkbPoint uint32 = iota + 1
+	wkbLineString
+	wkbPolygon
+	wkbMultiPoint
+	wkbMultiLineString
+	wkbMultiPolygon
+	wkbGeometryCollection
+)
+
+var (
+	errShortWKB = errors.New("unexpected end of WKB")
+
+	wkbNames = map[uint32]string{
+		wkbPoint:              "Point",
+		wkbLineString:         "LineString",
+		wkbPolygon:            "Polygon",
+		wkbMultiPoint:         "MultiPoint",
+		wkbMultiLineString:    "MultiLineString",
+		wkbMultiPolygon:       "MultiPolygon",
+		wkbGeometryCollection: "GeometryCollection",
+	}
+)
+
+// geometry is a decoded WKB geometry, coordinates are nested according to the geometry type.
+type geometry struct {
+	kind        uint32
+	point       []float64
+	points      [][]float64
+	rings       [][][]float64
+	polygons    [][][][]float64
+	collections []geometry
+}
+
+type wkbReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *wkbReader) byteOrder() (binary.ByteOrder, error) {
+	if r.pos >= len(r.data) {
+		return nil, errShortWKB
+	}
+	order := r.data[r.pos]
+	r.pos++
+	switch order {
+	case 0:
+		return binary.BigEndian, nil
+	case 1:
+		return binary.LittleEndian, nil
+	default:
+		return nil, fmt.Errorf("unknown %d WKB byte order", order)
+	}
+}
+
+func (r *wkbReader) uint32(order binary.ByteOrder) (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, errShortWKB
+	}
+	v := order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+// count reads number of elements, it is checked against the remaining data to avoid huge allocations for corrupted data.
+func (r *wkbReader) count(order binary.ByteOrder, minSize int) (int, error) {
+	n, err := r.uint32(order)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(r.data)-r.pos) {
+		return 0, errShortWKB
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) point(order binary.ByteOrder) ([]float64, error) {
+	if r.pos+16 > len(r.data) {
+		return nil, errShortWKB
+	}
+	x := math.Float64frombits(order.Uint64(r.data[r.pos:]))
+	y := math.Float64frombits(order.Uint64(r.data[r.pos+8:]))
+	r.pos += 16
+	return []float64{x, y}, nil
+}
+
+func (r *wkbReader) points(order binary.ByteOrder) ([][]float64, error) {
+	n, err := r.count(order, 16)
+	if err != nil {
+		return nil, err
+	}
+	points := make([][]float64, n)
+	for i := range points {
+		if points[i], err = r.point(order); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func (r *wkbReader) rings(order binary.ByteOrder) ([][][]float64, error) {
+	n, err := r.count(order, 4)
+	if err != nil {
+		return nil, err
+	}
+	rings := make([][][]float64, n)
+	for i := range rings {
+		if rings[i], err = r.points(order); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+// geometry reads the geometry, members of multi geometries and collections have their own headers.
+func (r *wkbReader) geometry() (geometry, error) {
+	order, err := r.byteOrder()
+	if err != nil {
+		return geometry{}, err
+	}
+	kind, err := r.uint32(order)
+	if err != nil {
+		return geometry{}, err
+	}
+	g := geometry{kind: kind}
+	switch kind {
+	case wkbPoint:
+		g.point, err = r.point(order)
+	case wkbLineString:
+		g.points, err = r.points(order)
+	case wkbPolygon:
+		g.rings, err = r.rings(order)
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		var n int
+		if n, err = r.count(order, 5); err != nil {
+			return geometry{}, err
+		}
+		g.collections = make([]geometry, n)
+		for i := range g.collections {
+			if g.collections[i], err = r.geometry(); err != nil {
+				return geometry{}, err
+			}
+			if kind != wkbGeometryCollection && g.collections[i].kind != kind-3 {
+				return geometry{}, fmt.Errorf("%s can't contain %s", wkbNames[kind], wkbNames[g.collections[i].kind])
+			}
+		}
+	default:
+		return geometry{}, fmt.Errorf("unsupported %d WKB geometry type", kind)
+	}
+	return g, err
+}
+
+// decodeGeometry decodes MySQL internal geometry format: SRID in little endian order followed by WKB.
+func decodeGeometry(data []byte) (uint32, geometry, error) {
+	if len(data) < sridSize {
+		return 0, geometry{}, errShortWKB
+	}
+	srid := binary.LittleEndian.Uint32(data)
+	r := &wkbReader{data: data[sridSize:]}
+	g, err := r.geometry()
+	if err != nil {
+		return 0, geometry{}, err
+	}
+	if r.pos != len(r.data) {
+		return 0, geometry{}, fmt.Errorf("%d unexpected bytes after WKB", len(r.data)-r.pos)
+	}
+	return srid, g, nil
+}
+
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func writePointWKT(builder *strings.Builder, point []float64) {
+	builder.WriteString(formatCoordinate(point[0]))
+	builder.WriteByte(' ')
+	builder.WriteString(formatCoordinate(point[1]))
+}
+
+func writePointsWKT(builder *strings.Builder, points [][]float64) {
+	builder.WriteByte('(')
+	for i, point := range points {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		writePointWKT(builder, point)
+	}
+	builder.WriteByte(')')
+}
+
+func writeRingsWKT(builder *strings.Builder, rings [][][]float64) {
+	builder.WriteByte('(')
+	for i, ring := range rings {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		writePointsWKT(builder, ring)
+	}
+	builder.WriteByte(')')
+}
+
+// writeWKT writes the geometry in the same text format as MySQL 8 ST_AsText function does.
+func (g geometry) writeWKT(builder *strings.Builder) {
+	builder.WriteString(strings.ToUpper(wkbNames[g.kind]))
+	switch g.kind {
+	case wkbPoint:
+		builder.WriteByte('(')
+		writePointWKT(builder, g.point)
+		builder.WriteByte(')')
+	case wkbLineString:
+		writePointsWKT(builder, g.points)
+	case wkbPolygon:
+		writeRingsWKT(builder, g.rings)
+	default:
+		if len(g.collections) == 0 {
+			builder.WriteString(" EMPTY")
+			return
+		}
+		builder.WriteByte('(')
+		for i, member := range g.collections {
+			if i > 0 {
+				builder.WriteByte(',')
+			}
+			switch g.kind {
+			case wkbMultiPoint:
+				builder.WriteByte('(')
+				writePointWKT(builder, member.point)
+				builder.WriteByte(')')
+			case wkbMultiLineString:
+				writePointsWKT(builder, member.points)
+			case wkbMultiPolygon:
+				writeRingsWKT(builder, member.rings)
+			default:
+				member.writeWKT(builder)
+			}
+		}
+		builder.WriteByte(')')
+	}
+}
+
+// geoJSON returns GeoJSON geometry object.
+func (g geometry) geoJSON() map[string]any {
+	result := map[string]any{"type": wkbNames[g.kind]}
+	switch g.kind {
+	case wkbPoint:
+		result["coordinates"] = g.point
+	case wkbLineString:
+		result["coordinates"] = g.points
+	case wkbPolygon:
+		result["coordinates"] = g.rings
+	case wkbGeometryCollection:
+		geometries := make([]map[string]any, len(g.collections))
+		for i, member := range g.collections {
+			geometries[i] = member.geoJSON()
+		}
+		result["geometries"] = geometries
+	default:
+		coordinates := make([]any, len(g.collections))
+		for i, member := range g.collections {
+			coordinates[i] = member.geoJSON()["coordinates"]
+		}
+		result["coordinates"] = coordinates
+	}
+	return result
+}
+
+// spatialConversion converts GEOMETRY values to WKT string or embedded GeoJSON object.
+// Non-zero SRID is published as EWKT prefix (SRID=4326;POINT(1 2)) and as GeoJSON named CRS.
+// Malformed or unsupported WKB value is published as binary like in binary format, so a single row doesn't stop the listener.
+func spatialConversion(format string, column string) conversion {
+	return func(value any) (any, error) {
+		data, ok := value.([]byte)
+		if !ok {
+			return value, nil
+		}
+		srid, g, err := decodeGeometry(data)
+		if err != nil {
+			logger.Warn().Err(err).Str("column", column).Int("size", len(data)).Msg("Geometry value can't be decoded, publish it as binary")
+			return value, nil
+		}
+		if format == wktFormat {
+			var builder strings.Builder
+			if srid != 0 {
+				builder.WriteString("SRID=" + strconv.FormatUint(uint64(srid), 10) + ";")
+			}
+			g.writeWKT(&builder)
+			return builder.String(), nil
+		}
+		object := g.geoJSON()
+		if srid != 0 {
+			object["crs"] = map[string]any{"type": "name", "properties": map[string]any{"name": "EPSG:" + strconv.FormatUint(uint64(srid), 10)}}
+		}
+		result, err := json.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("serialization GeoJSON failure: %w", err)
+		}
+		return json.RawMessage(result), nil
+	}
+}
+
+// vectorArray converts VECTOR value to array of float32 numbers stored in little endian order.
+// Malformed value and value with NaN or infinite numbers which JSON can't represent are published as binary.
+func vectorArray(column string) conversion {
+	return func(value any) (any, error) {
+		data, ok := value.([]byte)
+		if !ok {
+			return value, nil
+		}
+		if len(data)%4 != 0 {
+			logger.Warn().Str("column", column).Int("size", len(data)).Msg("VECTOR value size isn't multiple of 4, publish it as binary")
+			return value, nil
+		}
+		result := make([]float32, len(data)/4)
+		for i := range result {
+			result[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+			if f := float64(result[i]); math.IsNaN(f) || math.IsInf(f, 0) {
+				logger.Warn().Str("column", column).Int("index", i).Msg("VECTOR value has not finite number, publish it as binary")
+				return value, nil
+			}
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+const (
+	// DECIMAL formats
+	stringFormat = "string"
+	numberFormat = "number"
+	// temporal formats, text format keeps values received from server
+	isoFormat = "iso"
+	// ENUM and SET format, number format keeps ordinal number of ENUM and bit mask of SET
+	labelFormat = "label"
+	// BIT format, number format keeps integer value
+	bitsFormat = "bits"
+
+	keepZeroDate = "keep"
+	nullZeroDate = "null"
+
+	dateLayout     = "2006-01-02"
+	datetimeLayout = "2006-01-02 15:04:05"
+	isoLayout      = "2006-01-02T15:04:05"
+)
+
+// unsignedConversion reinterprets integer decoded as signed one by go-mysql according to the column width.
+func unsignedConversion(columnType byte) conversion {
+	return func(value any) (any, error) {
+		switch v := value.(type) {
+		case int8:
+			return uint8(v), nil
+		case int16:
+			return uint16(v), nil
+		case int32:
+			if columnType == mysql.MYSQL_TYPE_INT24 {
+				return uint32(v) & 0xFFFFFF, nil
+			}
+			return uint32(v), nil
+		case int64:
+			return uint64(v), nil
+		default:
+			return value, nil
+		}
+	}
+}
+
+// decimalNumber publishes DECIMAL value as JSON number keeping all its digits.
+func decimalNumber(value any) (any, error) {
+	if v, ok := value.(string); ok {
+		return json.Number(v), nil
+	}
+	return value, nil
+}
+
+// isZeroDate returns true for dates with zero year, month or day, they can't be represented in ISO-8601 format.
+func isZeroDate(value string) bool {
+	return len(value) < len(dateLayout) || value[0:4] == "0000" || value[5:7] == "00" || value[8:10] == "00"
+}
+
+// temporalConversion converts DATE, DATETIME and TIMESTAMP values. DATETIME values are interpreted in the location,
+// TIMESTAMP values are received in UTC and converted to the location.
+func temporalConversion(columnType byte, iso bool, zeroDate string, location *time.Location) conversion {
+	return func(value any) (any, error) {
+		text, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		if isZeroDate(text) {
+			if zeroDate == nullZeroDate {
+				return nil, nil
+			}
+			return text, nil
+		}
+		if !iso {
+			return text, nil
+		}
+		switch columnType {
+		case mysql.MYSQL_TYPE_DATE, mysql.MYSQL_TYPE_NEWDATE:
+			return text, nil
+		case mysql.MYSQL_TYPE_TIMESTAMP, mysql.MYSQL_TYPE_TIMESTAMP2:
+			t, err := time.ParseInLocation(datetimeLayout, text, time.UTC)
+			if err != nil {
+				return nil, fmt.Errorf("parsing '%s' timestamp failure: %w", text, err)
+			}
+			return t.In(location).Format(isoLayout + fraction(text) + "Z07:00"), nil
+		default:
+			t, err := time.ParseInLocation(datetimeLayout, text, location)
+			if err != nil {
+				return nil, fmt.Errorf("parsing '%s' datetime failure: %w", text, err)
+			}
+			return t.Format(isoLayout + fraction(text) + "Z07:00"), nil
+		}
+	}
+}
+
+// fraction returns layout of fractional seconds with the same precision as the value has.
+func fraction(text string) string {
+	i := strings.LastIndexByte(text, '.')
+	if i < 0 {
+		return ""
+	}
+	return "." + strings.Repeat("0", len(text)-i-1)
+}
+
+// enumConversion replaces 1-based ordinal number by the label, zero is the empty string written for invalid values.
+func enumConversion(labels []string) conversion {
+	return func(value any) (any, error) {
+		ordinal, ok := value.(int64)
+		if !ok {
+			return value, nil
+		}
+		if ordinal == 0 {
+			return "", nil
+		}
+		if ordinal < 0 || int(ordinal) > len(labels) {
+			return nil, fmt.Errorf("ENUM value %d is out of %d labels", ordinal, len(labels))
+		}
+		return labels[ordinal-1], nil
+	}
+}
+
+// setConversion replaces bit mask by comma separated labels like MySQL does.
+func setConversion(labels []string) conversion {
+	return func(value any) (any, error) {
+		mask, ok := value.(int64)
+		if !ok {
+			return value, nil
+		}
+		var selected []string
+		for i, label := range labels {
+			if mask&(1<<i) != 0 {
+				selected = append(selected, label)
+				mask &^= 1 << i
+			}
+		}
+		if mask != 0 {
+			return nil, fmt.Errorf("SET value %b has bits out of %d labels", mask, len(labels))
+		}
+		return strings.Join(selected, ","), nil
+	}
+}
+
+// bitsConversion publishes BIT value as string of zeros and ones with the column width.
+func bitsConversion(meta uint16) conversion {
+	width := int((meta>>8)*8 + meta&0xFF)
+	return func(value any) (any, error) {
+		if v, ok := value.(int64); ok {
+			return fmt.Sprintf("%0*b", width, uint64(v)), nil
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,413 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/th2-net/th2-common-go/pkg/log"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/database"
+)
+
+const (
+	textFormat   = "text"
+	binaryFormat = "binary"
+	jsonFormat   = "json"
+)
+
+var (
+	logger = log.ForComponent("values")
+
+	formats = map[string]struct{}{
+		textFormat:    {},
+		binaryFormat:  {},
+		jsonFormat:    {},
+		stringFormat:  {},
+		numberFormat:  {},
+		isoFormat:     {},
+		labelFormat:   {},
+		bitsFormat:    {},
+		wktFormat:     {},
+		geoJSONFormat: {},
+		arrayFormat:   {},
+	}
+)
+
+type columnFormat struct {
+	schema  database.NameMatcher
+	table   database.NameMatcher
+	column  database.NameMatcher
+	format  string
+	decoder *decoder
+}
+
+// Converter converts raw row values according to column types written to table map event
+// before the values are filtered, transformed by rules and put to a message.
+type Converter struct {
+	columns []columnFormat
+	// default formats of column types
+	json     string
+	decimal  string
+	temporal string
+	enum     string
+	bit      string
+	spatial  string
+	vector   string
+	zeroDate string
+	location *time.Location
+	// unknown collations are reported once
+	unknown map[uint64]struct{}
+}
+
+// conversion converts a value of the column, nil value is never passed.
+type conversion func(value any) (any, error)
+
+func New(cfg conf.Values) (*Converter, error) {
+	converter := &Converter{columns: make([]columnFormat, 0, len(cfg.Columns)), unknown: make(map[uint64]struct{})}
+	var err error
+	if converter.json, err = option("JSON", cfg.JSON, textFormat, jsonFormat); err != nil {
+		return nil, err
+	}
+	if converter.decimal, err = option("Decimal", cfg.Decimal, stringFormat, numberFormat); err != nil {
+		return nil, err
+	}
+	if converter.temporal, err = option("Temporal", cfg.Temporal, textFormat, isoFormat); err != nil {
+		return nil, err
+	}
+	if converter.enum, err = option("Enum", cfg.Enum, numberFormat, labelFormat); err != nil {
+		return nil, err
+	}
+	if converter.bit, err = option("Bit", cfg.Bit, numberFormat, bitsFormat); err != nil {
+		return nil, err
+	}
+	if converter.spatial, err = option("Spatial", cfg.Spatial, binaryFormat, wktFormat, geoJSONFormat); err != nil {
+		return nil, err
+	}
+	if converter.vector, err = option("Vector", cfg.Vector, binaryFormat, arrayFormat); err != nil {
+		return nil, err
+	}
+	if converter.zeroDate, err = option("ZeroDate", cfg.ZeroDate, keepZeroDate, nullZeroDate); err != nil {
+		return nil, err
+	}
+	converter.location = time.UTC
+	if cfg.Timezone != "" {
+		if converter.location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("loading '%s' timezone failure: %w", cfg.Timezone, err)
+		}
+	}
+	for _, columnCfg := range cfg.Columns {
+		column, err := newColumnFormat(columnCfg)
+		if err != nil {
+			return nil, fmt.Errorf("creating format of %s.%s.%s column failure: %w", columnCfg.Schema, columnCfg.Table, columnCfg.Column, err)
+		}
+		converter.columns = append(converter.columns, column)
+	}
+	return converter, nil
+}
+
+// option returns the value in lower case or the first allowed value by default.
+func option(name string, value string, allowed ...string) (string, error) {
+	if value == "" {
+		return allowed[0], nil
+	}
+	value = strings.ToLower(value)
+	for _, v := range allowed {
+		if v == value {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("unknown '%s' %s format, expected one of %v", value, name, allowed)
+}
+
+func newColumnFormat(cfg conf.ColumnFormat) (columnFormat, error) {
+	var result columnFormat
+	var err error
+	if result.schema, err = database.NewNameMatcher(cfg.Schema); err != nil {
+		return columnFormat{}, err
+	}
+	if result.table, err = database.NewNameMatcher(cfg.Table); err != nil {
+		return columnFormat{}, err
+	}
+	if result.column, err = database.NewNameMatcher(cfg.Column); err != nil {
+		return columnFormat{}, err
+	}
+	result.format = strings.ToLower(cfg.Format)
+	if _, ok := formats[result.format]; result.format != "" && !ok {
+		return columnFormat{}, fmt.Errorf("unknown '%s' format", cfg.Format)
+	}
+	if cfg.Charset != "" {
+		decoder, err := newDecoder(cfg.Charset)
+		if err != nil {
+			return columnFormat{}, err
+		}
+		result.decoder = &decoder
+	}
+	return result, nil
+}
+
+func (c *Converter) find(schema string, table string, column string) *columnFormat {
+	for i := range c.columns {
+		format := &c.columns[i]
+		if format.schema.Matches(schema) && format.table.Matches(table) && format.column.Matches(column) {
+			return format
+		}
+	}
+	return nil
+}
+
+// TimestampLocation returns the timezone TIMESTAMP values have to be formatted in by binlog parser. The values are formatted in UTC
+// when ISO format is configured for any column to be converted to the configured timezone, nil keeps the host timezone otherwise.
+func (c *Converter) TimestampLocation() *time.Location {
+	if c == nil {
+		return nil
+	}
+	if c.temporal == isoFormat {
+		return time.UTC
+	}
+	for _, column := range c.columns {
+		if column.format == isoFormat {
+			return time.UTC
+		}
+	}
+	return nil
+}
+
+// Convert returns rows with values converted according to the column types, the passed rows aren't modified.
+func (c *Converter) Convert(tableMap *replication.TableMapEvent, fields []string, rows [][]any) ([][]any, error) {
+	if c == nil || len(tableMap.ColumnType) == 0 {
+		return rows, nil
+	}
+	conversions, changed := c.conversions(tableMap, fields)
+	if !changed {
+		return rows, nil
+	}
+	result := make([][]any, len(rows))
+	for i, row := range rows {
+		converted := make([]any, len(row))
+		for j, value := range row {
+			if value == nil || j >= len(conversions) || conversions[j] == nil {
+				converted[j] = value
+				continue
+			}
+			var err error
+			if converted[j], err = conversions[j](value); err != nil {
+				return nil, fmt.Errorf("converting value of '%s' column failure: %w", columnName(fields, j), err)
+			}
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+func (c *Converter) conversions(tableMap *replication.TableMapEvent, fields []string) ([]conversion, bool) {
+	schema, table := string(tableMap.Schema), string(tableMap.Table)
+	collations := tableMap.CollationMap()
+	unsigned := tableMap.UnsignedMap()
+	conversions := make([]conversion, len(tableMap.ColumnType))
+	changed := false
+	for i := range tableMap.ColumnType {
+		format := c.find(schema, table, columnName(fields, i))
+		columnType := realType(tableMap, i)
+		switch columnType {
+		case mysql.MYSQL_TYPE_TINY, mysql.MYSQL_TYPE_SHORT, mysql.MYSQL_TYPE_INT24, mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_LONGLONG:
+			if unsigned[i] {
+				conversions[i] = unsignedConversion(columnType)
+			}
+		case mysql.MYSQL_TYPE_NEWDECIMAL:
+			if c.format(c.decimal, format) == numberFormat {
+				conversions[i] = decimalNumber
+			}
+		case mysql.MYSQL_TYPE_DATE, mysql.MYSQL_TYPE_NEWDATE, mysql.MYSQL_TYPE_DATETIME, mysql.MYSQL_TYPE_DATETIME2,
+			mysql.MYSQL_TYPE_TIMESTAMP, mysql.MYSQL_TYPE_TIMESTAMP2:
+			iso := c.format(c.temporal, format) == isoFormat
+			if iso || c.zeroDate != keepZeroDate {
+				conversions[i] = temporalConversion(columnType, iso, c.zeroDate, c.location)
+			}
+		case mysql.MYSQL_TYPE_ENUM, mysql.MYSQL_TYPE_SET:
+			if c.format(c.enum, format) == labelFormat {
+				conversions[i] = c.labelConversion(tableMap, i, columnType)
+			}
+		case mysql.MYSQL_TYPE_BIT:
+			if c.format(c.bit, format) == bitsFormat {
+				conversions[i] = bitsConversion(tableMap.ColumnMeta[i])
+			}
+		case mysql.MYSQL_TYPE_JSON:
+			conversions[i] = jsonConversion(c.format(c.json, format) == jsonFormat)
+		case mysql.MYSQL_TYPE_GEOMETRY:
+			if spatial := c.format(c.spatial, format); spatial == wktFormat || spatial == geoJSONFormat {
+				conversions[i] = spatialConversion(spatial, columnName(fields, i))
+			}
+		case mysql.MYSQL_TYPE_VECTOR:
+			if c.format(c.vector, format) == arrayFormat {
+				conversions[i] = vectorArray(columnName(fields, i))
+			}
+		default:
+			if tableMap.IsCharacterColumn(i) {
+				conversions[i] = c.characterConversion(collations, i, format)
+				if format != nil && format.format == jsonFormat {
+					conversions[i] = jsonTextConversion(conversions[i])
+				}
+			}
+		}
+		changed = changed || conversions[i] != nil
+	}
+	return conversions, changed
+}
+
+// format returns the format configured for the column or the default format of the column type.
+func (c *Converter) format(defaultFormat string, format *columnFormat) string {
+	if format != nil && format.format != "" {
+		return format.format
+	}
+	return defaultFormat
+}
+
+// labelConversion replaces ENUM and SET numbers by labels of the table map event, the numbers are kept when the labels aren't available.
+func (c *Converter) labelConversion(tableMap *replication.TableMapEvent, index int, columnType byte) conversion {
+	var labels []string
+	if columnType == mysql.MYSQL_TYPE_ENUM {
+		labels = tableMap.EnumStrValueMap()[index]
+	} else {
+		labels = tableMap.SetStrValueMap()[index]
+	}
+	if labels == nil {
+		return nil
+	}
+	if collation, ok := tableMap.EnumSetCollationMap()[index]; ok {
+		labels = c.decodeLabels(labels, collation)
+	}
+	if columnType == mysql.MYSQL_TYPE_ENUM {
+		return enumConversion(labels)
+	}
+	return setConversion(labels)
+}
+
+func (c *Converter) decodeLabels(labels []string, collation uint64) []string {
+	name, ok := collationCharset(collation)
+	if !ok {
+		c.reportUnknown(collation)
+		return labels
+	}
+	dec, err := newDecoder(name)
+	if err != nil || dec.binary() {
+		return labels
+	}
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		if result[i], err = dec.decode([]byte(label)); err != nil {
+			return labels
+		}
+	}
+	return result
+}
+
+// characterConversion decodes text by the column charset, values of binary columns are kept as bytes.
+// Values are kept as is when the server doesn't write charsets to table map event and the charset isn't configured.
+func (c *Converter) characterConversion(collations map[int]uint64, index int, format *columnFormat) conversion {
+	var dec *decoder
+	if format != nil && format.decoder != nil {
+		dec = format.decoder
+	} else if collation, ok := collations[index]; ok {
+		name, ok := collationCharset(collation)
+		if !ok {
+			c.reportUnknown(collation)
+			name = utf8mb4Charset
+		}
+		d, err := newDecoder(name)
+		if err != nil {
+			c.reportUnknown(collation)
+			d = decoder{charset: utf8mb4Charset}
+		}
+		dec = &d
+	}
+	var output string
+	if format != nil {
+		output = format.format
+	}
+	switch {
+	case output == binaryFormat || (output == "" && dec != nil && dec.binary()):
+		return toBytes
+	case dec != nil:
+		if dec.binary() {
+			// binary column is configured to be output as text
+			dec = &decoder{charset: utf8mb4Charset}
+		}
+		return func(value any) (any, error) {
+			switch v := value.(type) {
+			case string:
+				if dec.encoding == nil {
+					return v, nil
+				}
+				return dec.decode([]byte(v))
+			case []byte:
+				return dec.decode(v)
+			default:
+				return value, nil
+			}
+		}
+	case output == textFormat || output == jsonFormat:
+		return toText
+	default:
+		return nil
+	}
+}
+
+func (c *Converter) reportUnknown(collation uint64) {
+	if _, ok := c.unknown[collation]; ok {
+		return
+	}
+	c.unknown[collation] = struct{}{}
+	logger.Warn().Uint64("collation", collation).Msg("charset of the collation isn't supported, values are decoded as utf8mb4")
+}
+
+func toBytes(value any) (any, error) {
+	if v, ok := value.(string); ok {
+		return []byte(v), nil
+	}
+	return value, nil
+}
+
+func toText(value any) (any, error) {
+	if v, ok := value.([]byte); ok {
+		return string(v), nil
+	}
+	return value, nil
+}
+
+func columnName(fields []string, index int) string {
+	if index < len(fields) {
+		return fields[index]
+	}
+	return fmt.Sprintf("@%d", index+1)
+}
+
+// realType returns column type with ENUM and SET types distinguished from CHAR type.
+func realType(tableMap *replication.TableMapEvent, index int) byte {
+	columnType := tableMap.ColumnType[index]
+	if columnType == mysql.MYSQL_TYPE_STRING && index < len(tableMap.ColumnMeta) {
+		if real := byte(tableMap.ColumnMeta[index] >> 8); real == mysql.MYSQL_TYPE_ENUM || real == mysql.MYSQL_TYPE_SET {
+			return real
+		}
+	}
+	return columnType
+}
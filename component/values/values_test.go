@@ -0,0 +1,453 @@
+/*
+ Copyright 2025 Exactpro (Exactpro Systems Limited)
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+      https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package values_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	conf "github.com/th2-net/th2-listener-mysql-binlog-go/component/configuration"
+	"github.com/th2-net/th2-listener-mysql-binlog-go/component/values"
+)
+
+const (
+	latin1Collation  = 8
+	cp1251Collation  = 51
+	utf8mb4Collation = 255
+	binaryCollation  = 63
+)
+
+var fields = []string{"id", "name", "body", "data", "code", "note"}
+
+// tableMap describes INT, VARCHAR latin1, TEXT utf8mb4, BLOB, VARBINARY and CHAR cp1251 columns.
+func tableMap(charsets bool) *replication.TableMapEvent {
+	e := &replication.TableMapEvent{
+		Schema:      []byte("shop"),
+		Table:       []byte("customers"),
+		ColumnCount: 6,
+		ColumnType: []byte{
+			mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_VARCHAR, mysql.MYSQL_TYPE_BLOB,
+			mysql.MYSQL_TYPE_BLOB, mysql.MYSQL_TYPE_VARCHAR, mysql.MYSQL_TYPE_STRING,
+		},
+		ColumnMeta: []uint16{0, 50, 2, 2, 16, uint16(mysql.MYSQL_TYPE_STRING)<<8 | 10},
+	}
+	if charsets {
+		// default collation is followed by pairs of character column index and collation
+		e.DefaultCharset = []uint64{utf8mb4Collation, 0, latin1Collation, 2, binaryCollation, 3, binaryCollation, 4, cp1251Collation}
+	}
+	return e
+}
+
+func rows() [][]any {
+	return [][]any{
+		{int32(1), "caf\xe9", []byte("привет"), []byte{0xff, 0x00}, "\x01\x02", "\xcf\xf0\xe8\xe2\xe5\xf2"},
+		{int32(2), nil, nil, nil, nil, nil},
+	}
+}
+
+func TestConvertCharacterColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		charsets bool
+		columns  []conf.ColumnFormat
+		expected []any
+	}{
+		{
+			name:     "table map charsets",
+			charsets: true,
+			expected: []any{int32(1), "café", "привет", []byte{0xff, 0x00}, []byte("\x01\x02"), "Привет"},
+		},
+		{
+			name:     "column formats",
+			charsets: true,
+			columns: []conf.ColumnFormat{
+				{Schema: "shop", Table: "customers", Column: "body", Format: "binary"},
+				{Schema: "shop", Table: "*", Column: "code", Format: "text"},
+				{Schema: "shop", Table: "customers", Column: "note", Charset: "koi8r"},
+			},
+			expected: []any{int32(1), "café", []byte("привет"), []byte{0xff, 0x00}, "\x01\x02", "оПХБЕР"},
+		},
+		{
+			name:     "no charsets in table map",
+			charsets: false,
+			expected: []any{int32(1), "caf\xe9", []byte("привет"), []byte{0xff, 0x00}, "\x01\x02", "\xcf\xf0\xe8\xe2\xe5\xf2"},
+		},
+		{
+			name:     "configured charsets without table map charsets",
+			charsets: false,
+			columns: []conf.ColumnFormat{
+				{Schema: "shop", Table: "customers", Column: "name", Charset: "latin1"},
+				{Schema: "shop", Table: "customers", Column: "body", Format: "text"},
+				{Schema: "shop", Table: "customers", Column: "note", Charset: "cp1251"},
+			},
+			expected: []any{int32(1), "café", "привет", []byte{0xff, 0x00}, "\x01\x02", "Привет"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := values.New(conf.Values{Columns: tc.columns})
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := rows()
+			result, err := converter.Convert(tableMap(tc.charsets), fields, original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result[0], tc.expected) {
+				t.Fatalf("%#v expected, got %#v", tc.expected, result[0])
+			}
+			if !reflect.DeepEqual(result[1], rows()[1]) {
+				t.Fatalf("null values are expected to be kept, got %#v", result[1])
+			}
+			if !reflect.DeepEqual(original, rows()) {
+				t.Fatalf("passed rows are modified: %#v", original)
+			}
+		})
+	}
+}
+
+func TestInvalidColumnFormat(t *testing.T) {
+	for _, column := range []conf.ColumnFormat{
+		{Schema: "shop", Table: "customers", Column: "name", Format: "hex"},
+		{Schema: "shop", Table: "customers", Column: "name", Charset: "klingon"},
+		{Schema: "shop", Table: "customers", Column: ""},
+	} {
+		if _, err := values.New(conf.Values{Columns: []conf.ColumnFormat{column}}); err == nil {
+			t.Fatalf("error expected for %+v", column)
+		}
+	}
+}
+
+func TestConvertJSONColumns(t *testing.T) {
+	tableMap := &replication.TableMapEvent{
+		Schema:         []byte("shop"),
+		Table:          []byte("orders"),
+		ColumnCount:    3,
+		ColumnType:     []byte{mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_JSON, mysql.MYSQL_TYPE_BLOB},
+		ColumnMeta:     []uint16{0, 4, 4},
+		DefaultCharset: []uint64{utf8mb4Collation},
+	}
+	fields := []string{"id", "doc", "maria_doc"}
+	rows := [][]any{
+		{int32(1), `{"key":"value"}`, []byte(`{"list":[1,2]}`)},
+		{int32(1), &replication.JsonDiff{Op: replication.JsonDiffOperationReplace, Path: "$.key", Value: `"new"`}, []byte("not json")},
+		{int32(2), []byte{}, nil},
+		{int32(2), &replication.JsonDiff{Op: replication.JsonDiffOperationRemove, Path: "$.key"}, nil},
+	}
+	tests := []struct {
+		name     string
+		cfg      conf.Values
+		expected [][]any
+	}{
+		{
+			name: "text",
+			expected: [][]any{
+				{int32(1), `{"key":"value"}`, `{"list":[1,2]}`},
+				{int32(1), values.JSONDiff{Op: "Replace", Path: "$.key", Value: `"new"`}, "not json"},
+				{int32(2), "null", nil},
+				{int32(2), values.JSONDiff{Op: "Remove", Path: "$.key"}, nil},
+			},
+		},
+		{
+			name: "embedded",
+			cfg: conf.Values{JSON: "json", Columns: []conf.ColumnFormat{
+				{Schema: "shop", Table: "orders", Column: "maria_doc", Format: "json"},
+			}},
+			expected: [][]any{
+				{int32(1), json.RawMessage(`{"key":"value"}`), json.RawMessage(`{"list":[1,2]}`)},
+				{int32(1), values.JSONDiff{Op: "Replace", Path: "$.key", Value: json.RawMessage(`"new"`)}, "not json"},
+				{int32(2), json.RawMessage("null"), nil},
+				{int32(2), values.JSONDiff{Op: "Remove", Path: "$.key"}, nil},
+			},
+		},
+		{
+			name: "column format overrides default",
+			cfg: conf.Values{JSON: "json", Columns: []conf.ColumnFormat{
+				{Schema: "shop", Table: "orders", Column: "doc", Format: "text"},
+			}},
+			expected: [][]any{
+				{int32(1), `{"key":"value"}`, `{"list":[1,2]}`},
+				{int32(1), values.JSONDiff{Op: "Replace", Path: "$.key", Value: `"new"`}, "not json"},
+				{int32(2), "null", nil},
+				{int32(2), values.JSONDiff{Op: "Remove", Path: "$.key"}, nil},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := values.New(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tableMap, fields, rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("%#v expected, got %#v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestNormalizeTypes(t *testing.T) {
+	tableMap := &replication.TableMapEvent{
+		Schema:      []byte("shop"),
+		Table:       []byte("orders"),
+		ColumnCount: 12,
+		ColumnType: []byte{
+			mysql.MYSQL_TYPE_TINY, mysql.MYSQL_TYPE_INT24, mysql.MYSQL_TYPE_LONGLONG, mysql.MYSQL_TYPE_LONGLONG,
+			mysql.MYSQL_TYPE_NEWDECIMAL, mysql.MYSQL_TYPE_DATETIME2, mysql.MYSQL_TYPE_TIMESTAMP2, mysql.MYSQL_TYPE_DATE,
+			mysql.MYSQL_TYPE_STRING, mysql.MYSQL_TYPE_STRING, mysql.MYSQL_TYPE_BIT, mysql.MYSQL_TYPE_DATETIME2,
+		},
+		ColumnMeta: []uint16{
+			0, 0, 0, 0,
+			10<<8 | 2, 3, 0, 0,
+			uint16(mysql.MYSQL_TYPE_ENUM)<<8 | 1, uint16(mysql.MYSQL_TYPE_SET)<<8 | 1, 1<<8 | 2, 0,
+		},
+		// columns 0-2 are unsigned, bits are numbered from the most significant bit of numeric columns
+		SignednessBitmap: []byte{0b11100000},
+		EnumStrValue:     [][][]byte{{[]byte("NEW"), []byte("DONE")}},
+		SetStrValue:      [][][]byte{{[]byte("red"), []byte("green"), []byte("blue")}},
+	}
+	fields := []string{"tiny", "medium", "big", "signed", "amount", "created", "updated", "day", "status", "colors", "flags", "zero"}
+	rows := [][]any{{
+		int8(-1), int32(-1), int64(-1), int64(-1),
+		"12345.67", "2024-02-12 14:30:00.123", "2025-02-12 13:10:37", "2024-02-12",
+		int64(2), int64(5), int64(5), "0000-00-00 00:00:00",
+	}}
+	tests := []struct {
+		name     string
+		cfg      conf.Values
+		expected []any
+	}{
+		{
+			name: "defaults",
+			expected: []any{
+				uint8(255), uint32(0xFFFFFF), uint64(18446744073709551615), int64(-1),
+				"12345.67", "2024-02-12 14:30:00.123", "2025-02-12 13:10:37", "2024-02-12",
+				int64(2), int64(5), int64(5), "0000-00-00 00:00:00",
+			},
+		},
+		{
+			name: "normalized",
+			cfg: conf.Values{
+				Decimal: "number", Temporal: "iso", Timezone: "Europe/Moscow", ZeroDate: "null", Enum: "label", Bit: "bits",
+			},
+			expected: []any{
+				uint8(255), uint32(0xFFFFFF), uint64(18446744073709551615), int64(-1),
+				json.Number("12345.67"), "2024-02-12T14:30:00.123+03:00", "2025-02-12T16:10:37+03:00", "2024-02-12",
+				"DONE", "red,blue", "0000000101", nil,
+			},
+		},
+		{
+			name: "column formats",
+			cfg: conf.Values{
+				Decimal: "number", Temporal: "iso", Enum: "label", Bit: "bits",
+				Columns: []conf.ColumnFormat{
+					{Schema: "shop", Table: "orders", Column: "amount", Format: "string"},
+					{Schema: "shop", Table: "orders", Column: "updated", Format: "text"},
+					{Schema: "shop", Table: "orders", Column: "colors", Format: "number"},
+					{Schema: "shop", Table: "orders", Column: "flags", Format: "number"},
+				},
+			},
+			expected: []any{
+				uint8(255), uint32(0xFFFFFF), uint64(18446744073709551615), int64(-1),
+				"12345.67", "2024-02-12T14:30:00.123Z", "2025-02-12 13:10:37", "2024-02-12",
+				"DONE", int64(5), int64(5), "0000-00-00 00:00:00",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := values.New(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tableMap, fields, rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result[0], tc.expected) {
+				t.Fatalf("%#v expected, got %#v", tc.expected, result[0])
+			}
+		})
+	}
+}
+
+func TestTimestampLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      conf.Values
+		expected *time.Location
+	}{
+		{name: "text", cfg: conf.Values{Timezone: "Europe/Moscow"}, expected: nil},
+		{name: "iso", cfg: conf.Values{Temporal: "iso"}, expected: time.UTC},
+		{name: "iso column", cfg: conf.Values{Columns: []conf.ColumnFormat{{Schema: "shop", Table: "orders", Column: "updated", Format: "iso"}}}, expected: time.UTC},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := values.New(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if location := converter.TimestampLocation(); location != tc.expected {
+				t.Fatalf("%v location expected, got %v", tc.expected, location)
+			}
+		})
+	}
+}
+
+func TestInvalidValuesOptions(t *testing.T) {
+	for _, cfg := range []conf.Values{
+		{JSON: "yaml"},
+		{Decimal: "float"},
+		{Temporal: "unix"},
+		{Timezone: "Mars/Olympus"},
+		{ZeroDate: "epoch"},
+		{Enum: "ordinal"},
+		{Bit: "hex"},
+		{Spatial: "wkb"},
+		{Vector: "text"},
+	} {
+		if _, err := values.New(cfg); err == nil {
+			t.Fatalf("error expected for %+v", cfg)
+		}
+	}
+}
+
+// wkb builds MySQL internal geometry value: SRID followed by little endian WKB of the geometry parts.
+func wkb(srid uint32, parts ...any) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, srid)
+	for _, part := range parts {
+		switch v := part.(type) {
+		case uint32:
+			data = binary.LittleEndian.AppendUint32(data, v)
+		case float64:
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		case byte:
+			data = append(data, v)
+		}
+	}
+	return data
+}
+
+func TestConvertSpatialColumns(t *testing.T) {
+	const le = byte(1)
+	tableMap := &replication.TableMapEvent{
+		Schema:      []byte("geo"),
+		Table:       []byte("places"),
+		ColumnCount: 6,
+		ColumnType: []byte{
+			mysql.MYSQL_TYPE_LONG, mysql.MYSQL_TYPE_GEOMETRY, mysql.MYSQL_TYPE_GEOMETRY,
+			mysql.MYSQL_TYPE_GEOMETRY, mysql.MYSQL_TYPE_GEOMETRY, mysql.MYSQL_TYPE_VECTOR,
+		},
+		ColumnMeta: []uint16{0, 4, 4, 4, 4, 4},
+	}
+	fields := []string{"id", "location", "area", "stops", "mixed", "embedding"}
+	embedding := binary.LittleEndian.AppendUint32(nil, math.Float32bits(1.5))
+	embedding = binary.LittleEndian.AppendUint32(embedding, math.Float32bits(-2))
+	rows := [][]any{{
+		int32(1),
+		wkb(4326, le, uint32(1), 1.5, -2.0),
+		wkb(0, le, uint32(3), uint32(1), uint32(4), 0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 0.0),
+		wkb(0, le, uint32(4), uint32(2), le, uint32(1), 1.0, 1.0, le, uint32(1), 2.0, 2.0),
+		wkb(0, le, uint32(7), uint32(2), le, uint32(1), 3.0, 4.0, le, uint32(2), uint32(2), 0.0, 0.0, 1.0, 1.0),
+		embedding,
+	}}
+	tests := []struct {
+		name     string
+		cfg      conf.Values
+		expected []any
+	}{
+		{
+			name:     "defaults",
+			expected: rows[0],
+		},
+		{
+			name: "wkt",
+			cfg:  conf.Values{Spatial: "wkt", Vector: "array"},
+			expected: []any{
+				int32(1), "SRID=4326;POINT(1.5 -2)", "POLYGON((0 0,1 0,1 1,0 0))", "MULTIPOINT((1 1),(2 2))",
+				"GEOMETRYCOLLECTION(POINT(3 4),LINESTRING(0 0,1 1))", []float32{1.5, -2},
+			},
+		},
+		{
+			name: "geojson",
+			cfg: conf.Values{
+				Spatial: "geojson",
+				Columns: []conf.ColumnFormat{{Schema: "geo", Table: "places", Column: "area", Format: "wkt"}},
+			},
+			expected: []any{
+				int32(1),
+				json.RawMessage(`{"coordinates":[1.5,-2],"crs":{"properties":{"name":"EPSG:4326"},"type":"name"},"type":"Point"}`),
+				"POLYGON((0 0,1 0,1 1,0 0))",
+				json.RawMessage(`{"coordinates":[[1,1],[2,2]],"type":"MultiPoint"}`),
+				json.RawMessage(`{"geometries":[{"coordinates":[3,4],"type":"Point"},{"coordinates":[[0,0],[1,1]],"type":"LineString"}],"type":"GeometryCollection"}`),
+				embedding,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			converter, err := values.New(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := converter.Convert(tableMap, fields, rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result[0], tc.expected) {
+				t.Fatalf("%#v expected, got %#v", tc.expected, result[0])
+			}
+		})
+	}
+}
+
+func TestUndecodableSpatialValue(t *testing.T) {
+	tableMap := &replication.TableMapEvent{
+		ColumnCount: 2,
+		ColumnType:  []byte{mysql.MYSQL_TYPE_GEOMETRY, mysql.MYSQL_TYPE_VECTOR},
+		ColumnMeta:  []uint16{4, 4},
+	}
+	converter, err := values.New(conf.Values{Spatial: "wkt", Vector: "array"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedding := binary.LittleEndian.AppendUint32(nil, math.Float32bits(1.5))
+	for _, row := range [][]any{
+		{wkb(0, byte(1), uint32(1), 1.0), embedding[:3]},
+		{wkb(0, byte(1), uint32(9)), binary.LittleEndian.AppendUint32(embedding, math.Float32bits(float32(math.NaN())))},
+		{wkb(0, byte(1), uint32(4), uint32(1), byte(1), uint32(2), uint32(0)), binary.LittleEndian.AppendUint32(embedding, math.Float32bits(float32(math.Inf(1))))},
+	} {
+		result, err := converter.Convert(tableMap, []string{"shape", "embedding"}, [][]any{row})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result[0], row) {
+			t.Fatalf("binary values expected, got %#v", result[0])
+		}
+	}
+}
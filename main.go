@@ -130,10 +130,15 @@ func main() {
 	readinessMonitor.Enable()
 	defer readinessMonitor.Disable()
 
-	listener, err := listener.New(batcher, conf.Connection, conf.Schemas, componentConf.Book, group, alias, int(maxSize))
+	listener, err := listener.New(batcher, mqMod.GetEventRouter(), rootEventID, conf, componentConf.Book, group, alias, int(maxSize))
 	if err != nil {
 		logger.Panic().Err(err).Msg("Listener creation failure")
 	}
+	defer func(closer io.Closer) {
+		if err := closer.Close(); err != nil {
+			logger.Error().Err(err).Msg("cannot close listener")
+		}
+	}(listener)
 
 	lwdp, err := fetcher.NewLwdpFetcher(grpcMod.GetRouter())
 	if err != nil {